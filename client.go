@@ -0,0 +1,222 @@
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	"github.com/mh-cbon/astutil"
+	"github.com/mh-cbon/httper/utils"
+)
+
+// processClient generates an http client of todo,
+// its requests follows the conventions of the httped type.
+func processClient(mode string, todo utils.TransformArg, fileOut *utils.FileOut) error {
+
+	dest := &fileOut.Body
+	srcName := todo.FromTypeName
+	destName := todo.ToTypeName
+
+	prog := astutil.GetProgramFast(todo.FromPkgPath)
+	pkg := prog.Package(todo.FromPkgPath)
+	foundMethods := astutil.FindMethods(pkg)
+	findDurationTypes(prog)
+
+	srcConcrete := astutil.GetUnpointedType(srcName)
+	destConcrete := astutil.GetUnpointedType(destName)
+
+	fileOut.AddImport("net/http", "")
+	fileOut.AddImport("github.com/mh-cbon/httper/lib", "httper")
+
+	// qualifier records the packages of the types
+	// used by the client to import them.
+	qualifier := importQualifier(pkg, fileOut)
+
+	// Declare the new type
+	fmt.Fprintf(dest, `
+// %v is an http client of %v.
+type %v struct{
+	client *httper.Client
+}
+`, destConcrete, srcName, destConcrete)
+
+	// Make the constructor
+	fmt.Fprintf(dest, `// New%v constructs an http client of %v
+func New%v(baseURL string, hc *http.Client) *%v {
+	return &%v{
+		client: httper.NewClient(baseURL, hc),
+	}
+}
+`, destConcrete, srcName, destConcrete, destConcrete, destConcrete)
+
+	for _, m := range foundMethods[srcConcrete] {
+		methodName := astutil.MethodName(m)
+		paramNames := astutil.MethodParamNames(m)
+		paramTypes := astutil.MethodParamTypes(m)
+		paramTypesInfo := methodParamTypesInfo(pkg, m)
+		resultTypesInfo := methodResultTypesInfo(pkg, m)
+
+		// ensure it is desired to facade this method.
+		if astutil.IsExported(methodName) == false {
+			continue
+		}
+		if methodName == "UnmarshalJSON" || methodName == "MarshalJSON" {
+			continue
+		}
+
+		comment := astutil.GetComment(prog, m.Pos())
+		annotations := getAnnotations(comment)
+		route, err := getRoute(mode, methodName, paramNames, paramTypesInfo, annotations)
+		if err != nil {
+			return fmt.Errorf("%v.%v: %v", srcName, methodName, err)
+		}
+
+		params := []string{}
+		request := ""
+		hasBody := false
+
+		lParamNames := strings.Split(paramNames, ",")
+		lParamTypes := strings.Split(paramTypes, ",")
+		for i, p := range lParamNames {
+			p = strings.TrimSpace(p)
+			paramType := strings.TrimSpace(lParamTypes[i])
+
+			if p == "" {
+				// the method has no parameters.
+				continue
+			}
+			if paramType == "context.Context" {
+				params = append(params, fmt.Sprintf("%v %v", p, types.TypeString(paramTypesInfo[i], qualifier)))
+				request += fmt.Sprintf("req.Context = %v\n", p)
+				continue
+			}
+			if fields := getBindFields(paramTypesInfo[i]); len(fields) > 0 {
+				params = append(params, fmt.Sprintf("%v %v", p, types.TypeString(paramTypesInfo[i], qualifier)))
+				bind := ""
+				for _, f := range fields {
+					code, body, _ := clientParam(route.Path, f.Prefix, f.Name, p+"."+f.Path)
+					bind += code
+					hasBody = hasBody || body
+				}
+				if _, ok := paramTypesInfo[i].(*types.Pointer); ok {
+					bind = fmt.Sprintf("if %v != nil {\n%v}\n", p, bind)
+				}
+				request += bind
+				continue
+			}
+			if !isConvetionnedParam(mode, p) {
+				continue
+			}
+			if paramType == "httper.Cookier" || paramType == "httper.Sessionner" {
+				continue
+			}
+
+			if p == reqBodyVarName {
+				params = append(params, fmt.Sprintf("%v %v", p, types.TypeString(paramTypesInfo[i], qualifier)))
+				hasBody = true
+				request += fmt.Sprintf("req.Body = %v\n", p)
+				continue
+			}
+
+			prefix := getParamConvention(mode, p)
+			// the types defined from time.Duration are sent as durations.
+			expr := p
+			elemType := sliceElem(paramTypesInfo[i])
+			if elemType != nil && isDurationType(elemType) && types.TypeString(elemType, nil) != "time.Duration" {
+				expr = "time.Duration(v)"
+			} else if isDurationType(paramTypesInfo[i]) && types.TypeString(paramTypesInfo[i], nil) != "time.Duration" {
+				expr = fmt.Sprintf("time.Duration(%v)", p)
+			}
+			code, body, ok := clientParam(route.Path, prefix, getParamName(prefix, p), expr)
+			if !ok {
+				// other conventions are about the server state,
+				// they can not be sent.
+				continue
+			}
+			params = append(params, fmt.Sprintf("%v %v", p, types.TypeString(paramTypesInfo[i], qualifier)))
+			if expr != p {
+				fileOut.AddImport("time", "")
+			}
+			if expr == "time.Duration(v)" {
+				code = fmt.Sprintf("for _, v := range %v {\n%v}\n", p, code)
+			}
+			request += code
+			hasBody = hasBody || body
+		}
+
+		// the client always returns an error,
+		// the other results are decoded from the response.
+		results := []string{}
+		decodeArgs := []string{}
+		readBody := false
+		for i, t := range resultTypesInfo {
+			if isErrorType(t) {
+				continue
+			}
+			resultName := fmt.Sprintf("ret%v", i)
+			results = append(results, fmt.Sprintf("%v %v", resultName, types.TypeString(t, qualifier)))
+			decodeArgs = append(decodeArgs, "&"+resultName)
+			if isIOReaderType(t) {
+				readBody = true
+			}
+		}
+		results = append(results, "err error")
+
+		decode := fmt.Sprintf("err = t.client.Decode(%v)\n", strings.Join(append([]string{"res"}, decodeArgs...), ", "))
+		if readBody && len(decodeArgs) == 1 {
+			decode = fmt.Sprintf("%v, err = t.client.Read(res)\n", decodeArgs[0][1:])
+		}
+
+		returns := []string{}
+		for _, r := range results {
+			returns = append(returns, strings.Split(r, " ")[0])
+		}
+
+		fmt.Fprintf(dest, `// %v invokes %v.%v over http.
+func (t *%v) %v(%v) (%v) {
+	req := httper.NewClientRequest(%q, %q)
+	%v
+	res, err := t.client.Do(req)
+	if err != nil {
+		return %v
+	}
+	%v
+	return %v
+}
+`, methodName, srcName, methodName,
+			destConcrete, methodName, strings.Join(params, ", "), strings.Join(results, ", "),
+			getRouteMethods(route, hasBody)[0], route.Path,
+			request,
+			strings.Join(returns, ", "),
+			decode,
+			strings.Join(returns, ", "))
+		fmt.Fprintln(dest)
+	}
+
+	return nil
+}
+
+// clientParam returns the code to send the expression expr as the data name of prefix
+// to the route path, body is true when it is sent in the request body,
+// ok is false when it can not be sent.
+func clientParam(path, prefix, name, expr string) (code string, body bool, ok bool) {
+	if prefix == "url" && !strings.Contains(path, "{"+name) {
+		// url data are also read from the query.
+		prefix = "get"
+	}
+	switch prefix {
+	case "get", "req":
+		return fmt.Sprintf("req.AddQuery(%q, %v)\n", name, expr), false, true
+	case "url", "route":
+		return fmt.Sprintf("req.SetVar(%q, %v)\n", name, expr), false, true
+	case "cookie":
+		return fmt.Sprintf("req.AddCookie(%q, %v)\n", name, expr), false, true
+	case "header":
+		return fmt.Sprintf("req.AddHeader(%q, %v)\n", name, expr), false, true
+	case "file":
+		return fmt.Sprintf("if err = req.AddFile(%q, %v); err != nil {\nreturn\n}\n", name, expr), true, true
+	case "post":
+		return fmt.Sprintf("req.AddForm(%q, %v)\n", name, expr), true, true
+	}
+	return "", false, false
+}
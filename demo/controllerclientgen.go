@@ -0,0 +1,118 @@
+package main
+
+// file generated by
+// github.com/mh-cbon/httper
+// do not edit
+
+import (
+	httper "github.com/mh-cbon/httper/lib"
+	"io"
+	"net/http"
+)
+
+// ControllerClientGen is an http client of *ControllerJSONGen.
+type ControllerClientGen struct {
+	client *httper.Client
+}
+
+// NewControllerClientGen constructs an http client of *ControllerJSONGen
+func NewControllerClientGen(baseURL string, hc *http.Client) *ControllerClientGen {
+	return &ControllerClientGen{
+		client: httper.NewClient(baseURL, hc),
+	}
+}
+
+// GetByID invokes *ControllerJSONGen.GetByID over http.
+func (t *ControllerClientGen) GetByID(urlID int) (ret0 io.Reader, err error) {
+	req := httper.NewClientRequest("GET", "/getbyid/{id}")
+	req.SetVar("id", urlID)
+
+	res, err := t.client.Do(req)
+	if err != nil {
+		return ret0, err
+	}
+	ret0, err = t.client.Read(res)
+
+	return ret0, err
+}
+
+// UpdateByID invokes *ControllerJSONGen.UpdateByID over http.
+func (t *ControllerClientGen) UpdateByID(urlID int, reqBody io.Reader) (ret0 io.Reader, err error) {
+	req := httper.NewClientRequest("PUT", "/updatebyid/{id}")
+	req.SetVar("id", urlID)
+	req.Body = reqBody
+
+	res, err := t.client.Do(req)
+	if err != nil {
+		return ret0, err
+	}
+	ret0, err = t.client.Read(res)
+
+	return ret0, err
+}
+
+// DeleteByID invokes *ControllerJSONGen.DeleteByID over http.
+func (t *ControllerClientGen) DeleteByID(REQid int) (ret0 io.Reader, err error) {
+	req := httper.NewClientRequest("DELETE", "/deletebyid")
+	req.AddQuery("id", REQid)
+
+	res, err := t.client.Do(req)
+	if err != nil {
+		return ret0, err
+	}
+	ret0, err = t.client.Read(res)
+
+	return ret0, err
+}
+
+// TestVars1 invokes *ControllerJSONGen.TestVars1 over http.
+func (t *ControllerClientGen) TestVars1() (ret0 io.Reader, err error) {
+	req := httper.NewClientRequest("GET", "/testvars1")
+
+	res, err := t.client.Do(req)
+	if err != nil {
+		return ret0, err
+	}
+	ret0, err = t.client.Read(res)
+
+	return ret0, err
+}
+
+// TestCookier invokes *ControllerJSONGen.TestCookier over http.
+func (t *ControllerClientGen) TestCookier() (ret0 io.Reader, err error) {
+	req := httper.NewClientRequest("GET", "/testcookier")
+
+	res, err := t.client.Do(req)
+	if err != nil {
+		return ret0, err
+	}
+	ret0, err = t.client.Read(res)
+
+	return ret0, err
+}
+
+// TestSessionner invokes *ControllerJSONGen.TestSessionner over http.
+func (t *ControllerClientGen) TestSessionner() (ret0 io.Reader, err error) {
+	req := httper.NewClientRequest("GET", "/testsessionner")
+
+	res, err := t.client.Do(req)
+	if err != nil {
+		return ret0, err
+	}
+	ret0, err = t.client.Read(res)
+
+	return ret0, err
+}
+
+// TestRPCer invokes *ControllerJSONGen.TestRPCer over http.
+func (t *ControllerClientGen) TestRPCer() (ret0 io.Reader, err error) {
+	req := httper.NewClientRequest("GET", "/testrpcer")
+
+	res, err := t.client.Do(req)
+	if err != nil {
+		return ret0, err
+	}
+	ret0, err = t.client.Read(res)
+
+	return ret0, err
+}
@@ -5,6 +5,7 @@ package main
 // do not edit
 
 import (
+	"github.com/gorilla/mux"
 	httper "github.com/mh-cbon/httper/lib"
 	"io"
 	"net/http"
@@ -15,28 +16,60 @@ var xxStrconvAtoi = strconv.Atoi
 var xxIoCopy = io.Copy
 var xxHTTPOk = http.StatusOK
 
+// ControllerHTTPGenEmbed is the interface of the methods of *ControllerJSONGen wrapped by ControllerHTTPGen.
+type ControllerHTTPGenEmbed interface {
+	GetByID(urlID int) (io.Reader, error)
+	UpdateByID(urlID int, reqBody io.Reader) (io.Reader, error)
+	DeleteByID(REQid int) (io.Reader, error)
+	TestVars1(w http.ResponseWriter, r *http.Request) (io.Reader, error)
+	TestCookier(c httper.Cookier) (io.Reader, error)
+	TestSessionner(s httper.Sessionner) (io.Reader, error)
+	TestRPCer(r *http.Request) (io.Reader, error)
+}
+
 // ControllerHTTPGen is an httper of *ControllerJSONGen.
 // ControllerJSONGen is jsoner of *Controller.
 // Controller of some resources.
 type ControllerHTTPGen struct {
-	embed     *ControllerJSONGen
-	cookier   httper.CookieProvider
-	dataer    httper.DataerProvider
-	sessioner httper.SessionProvider
-	finalizer httper.Finalizer
+	embed             ControllerHTTPGenEmbed
+	cookier           httper.CookieProvider
+	dataer            httper.DataerProvider
+	sessioner         httper.SessionProvider
+	finalizer         httper.Finalizer
+	bodyDecoder       httper.BodyDecoder
+	authenticator     httper.Authenticator
+	authorizer        httper.Authorizer
+	middlewares       []httper.Middleware
+	methodMiddlewares map[string][]httper.Middleware
 }
 
 // NewControllerHTTPGen constructs an httper of *ControllerJSONGen
-func NewControllerHTTPGen(embed *ControllerJSONGen, finalizer httper.Finalizer) *ControllerHTTPGen {
-	if finalizer == nil {
-		finalizer = &httper.HTTPFinalizer{}
+func NewControllerHTTPGen(embed ControllerHTTPGenEmbed, finalizer httper.Finalizer) *ControllerHTTPGen {
+	return NewControllerHTTPGenWithOptions(embed, httper.WithFinalizer(finalizer))
+}
+
+// NewControllerHTTPGenWithOptions constructs an httper of *ControllerJSONGen configured by opts.
+func NewControllerHTTPGenWithOptions(embed ControllerHTTPGenEmbed, opts ...httper.Option) *ControllerHTTPGen {
+	o := &httper.Options{
+		Finalizer:   &httper.HTTPFinalizer{},
+		Cookier:     &httper.CookieHelperProvider{},
+		Dataer:      &httper.GorillaHTTPDataProvider{},
+		Sessioner:   &httper.GorillaSessionProvider{},
+		BodyDecoder: &httper.ContentBodyDecoder{},
+		Authorizer:  &httper.DefaultAuthorizer{},
 	}
+	o.Apply(opts...)
 	ret := &ControllerHTTPGen{
-		embed:     embed,
-		cookier:   &httper.CookieHelperProvider{},
-		dataer:    &httper.GorillaHTTPDataProvider{},
-		sessioner: &httper.GorillaSessionProvider{},
-		finalizer: finalizer,
+		embed:             embed,
+		cookier:           o.Cookier,
+		dataer:            o.Dataer,
+		sessioner:         o.Sessioner,
+		finalizer:         o.Finalizer,
+		bodyDecoder:       o.BodyDecoder,
+		authenticator:     o.Authenticator,
+		authorizer:        o.Authorizer,
+		middlewares:       o.Middlewares,
+		methodMiddlewares: map[string][]httper.Middleware{},
 	}
 	return ret
 }
@@ -46,20 +79,26 @@ func NewControllerHTTPGen(embed *ControllerJSONGen, finalizer httper.Finalizer)
 // Other parameters are passed straight
 // GetByID ...
 func (t *ControllerHTTPGen) GetByID(w http.ResponseWriter, r *http.Request) {
-	var urlID int
-	tempurlID, err := strconv.Atoi(t.dataer.Make(w, r).Get("url", "id"))
-	if err != nil && t.finalizer.HandleError(err, w, r) {
-		return
-	}
-	urlID = tempurlID
-
-	res, err := t.embed.GetByID(urlID)
-	if err != nil && t.finalizer.HandleError(err, w, r) {
-		return
-	}
-
-	t.finalizer.HandleSuccess(w, res)
-
+	httper.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httpData := t.dataer.Make(w, r)
+		var urlID int
+		tempurlID := httpData.Get("url", "id")
+		if tempurlID != "" {
+			convurlID, err := strconv.Atoi(tempurlID)
+			if err != nil && t.finalizer.HandleError(httper.ParamError("url", "id", err), w, r) {
+				return
+			}
+			urlID = convurlID
+		}
+
+		res, err := t.embed.GetByID(urlID)
+		if err != nil && t.finalizer.HandleError(err, w, r) {
+			return
+		}
+
+		t.finalizer.HandleSuccess(w, r, res)
+
+	}), t.middlewares...).ServeHTTP(w, r)
 }
 
 // UpdateByID invoke *ControllerJSONGen.UpdateByID using the request body as a json payload.
@@ -67,21 +106,27 @@ func (t *ControllerHTTPGen) GetByID(w http.ResponseWriter, r *http.Request) {
 // Other parameters are passed straight
 // UpdateByID ...
 func (t *ControllerHTTPGen) UpdateByID(w http.ResponseWriter, r *http.Request) {
-	var urlID int
-	tempurlID, err := strconv.Atoi(t.dataer.Make(w, r).Get("url", "id"))
-	if err != nil && t.finalizer.HandleError(err, w, r) {
-		return
-	}
-	urlID = tempurlID
-	reqBody := r.Body
-
-	res, err := t.embed.UpdateByID(urlID, reqBody)
-	if err != nil && t.finalizer.HandleError(err, w, r) {
-		return
-	}
-
-	t.finalizer.HandleSuccess(w, res)
-
+	httper.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httpData := t.dataer.Make(w, r)
+		var urlID int
+		tempurlID := httpData.Get("url", "id")
+		if tempurlID != "" {
+			convurlID, err := strconv.Atoi(tempurlID)
+			if err != nil && t.finalizer.HandleError(httper.ParamError("url", "id", err), w, r) {
+				return
+			}
+			urlID = convurlID
+		}
+		reqBody := r.Body
+
+		res, err := t.embed.UpdateByID(urlID, reqBody)
+		if err != nil && t.finalizer.HandleError(err, w, r) {
+			return
+		}
+
+		t.finalizer.HandleSuccess(w, r, res)
+
+	}), t.middlewares...).ServeHTTP(w, r)
 }
 
 // DeleteByID invoke *ControllerJSONGen.DeleteByID using the request body as a json payload.
@@ -89,20 +134,26 @@ func (t *ControllerHTTPGen) UpdateByID(w http.ResponseWriter, r *http.Request) {
 // Other parameters are passed straight
 // DeleteByID ...
 func (t *ControllerHTTPGen) DeleteByID(w http.ResponseWriter, r *http.Request) {
-	var REQid int
-	tempREQid, err := strconv.Atoi(t.dataer.Make(w, r).Get("req", "id"))
-	if err != nil && t.finalizer.HandleError(err, w, r) {
-		return
-	}
-	REQid = tempREQid
-
-	res, err := t.embed.DeleteByID(REQid)
-	if err != nil && t.finalizer.HandleError(err, w, r) {
-		return
-	}
-
-	t.finalizer.HandleSuccess(w, res)
-
+	httper.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httpData := t.dataer.Make(w, r)
+		var REQid int
+		tempREQid := httpData.Get("req", "id")
+		if tempREQid != "" {
+			convREQid, err := strconv.Atoi(tempREQid)
+			if err != nil && t.finalizer.HandleError(httper.ParamError("req", "id", err), w, r) {
+				return
+			}
+			REQid = convREQid
+		}
+
+		res, err := t.embed.DeleteByID(REQid)
+		if err != nil && t.finalizer.HandleError(err, w, r) {
+			return
+		}
+
+		t.finalizer.HandleSuccess(w, r, res)
+
+	}), t.middlewares...).ServeHTTP(w, r)
 }
 
 // TestVars1 invoke *ControllerJSONGen.TestVars1 using the request body as a json payload.
@@ -110,14 +161,16 @@ func (t *ControllerHTTPGen) DeleteByID(w http.ResponseWriter, r *http.Request) {
 // Other parameters are passed straight
 // TestVars1 ...
 func (t *ControllerHTTPGen) TestVars1(w http.ResponseWriter, r *http.Request) {
+	httper.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
-	res, err := t.embed.TestVars1(w, r)
-	if err != nil && t.finalizer.HandleError(err, w, r) {
-		return
-	}
+		res, err := t.embed.TestVars1(w, r)
+		if err != nil && t.finalizer.HandleError(err, w, r) {
+			return
+		}
 
-	t.finalizer.HandleSuccess(w, res)
+		t.finalizer.HandleSuccess(w, r, res)
 
+	}), t.middlewares...).ServeHTTP(w, r)
 }
 
 // TestCookier invoke *ControllerJSONGen.TestCookier using the request body as a json payload.
@@ -125,16 +178,18 @@ func (t *ControllerHTTPGen) TestVars1(w http.ResponseWriter, r *http.Request) {
 // Other parameters are passed straight
 // TestCookier ...
 func (t *ControllerHTTPGen) TestCookier(w http.ResponseWriter, r *http.Request) {
-	var c httper.Cookier
-	c = t.cookier.Make(w, r)
+	httper.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var c httper.Cookier
+		c = t.cookier.Make(w, r)
 
-	res, err := t.embed.TestCookier(c)
-	if err != nil && t.finalizer.HandleError(err, w, r) {
-		return
-	}
+		res, err := t.embed.TestCookier(c)
+		if err != nil && t.finalizer.HandleError(err, w, r) {
+			return
+		}
 
-	t.finalizer.HandleSuccess(w, res)
+		t.finalizer.HandleSuccess(w, r, res)
 
+	}), t.middlewares...).ServeHTTP(w, r)
 }
 
 // TestSessionner invoke *ControllerJSONGen.TestSessionner using the request body as a json payload.
@@ -142,28 +197,59 @@ func (t *ControllerHTTPGen) TestCookier(w http.ResponseWriter, r *http.Request)
 // Other parameters are passed straight
 // TestSessionner ...
 func (t *ControllerHTTPGen) TestSessionner(w http.ResponseWriter, r *http.Request) {
-	var s httper.Sessionner
-	s = t.sessioner.Make(w, r)
+	httper.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var s httper.Sessionner
+		s = t.sessioner.Make(w, r)
 
-	res, err := t.embed.TestSessionner(s)
-	if err != nil && t.finalizer.HandleError(err, w, r) {
-		return
-	}
+		res, err := t.embed.TestSessionner(s)
+		if err != nil && t.finalizer.HandleError(err, w, r) {
+			return
+		}
+
+		if saveErr := s.Save(); saveErr != nil && t.finalizer.HandleError(saveErr, w, r) {
+			return
+		}
 
-	t.finalizer.HandleSuccess(w, res)
+		t.finalizer.HandleSuccess(w, r, res)
 
+	}), t.middlewares...).ServeHTTP(w, r)
 }
 
 // TestRPCer invoke *ControllerJSONGen.TestRPCer using the request body as a json payload.
 // TestRPCer Decodes r as json to invoke *Controller.TestRPCer.
 // TestRPCer ...
 func (t *ControllerHTTPGen) TestRPCer(w http.ResponseWriter, r *http.Request) {
+	httper.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
-	res, err := t.embed.TestRPCer(r)
-	if err != nil && t.finalizer.HandleError(err, w, r) {
-		return
-	}
+		res, err := t.embed.TestRPCer(r)
+		if err != nil && t.finalizer.HandleError(err, w, r) {
+			return
+		}
+
+		t.finalizer.HandleSuccess(w, r, res)
 
-	t.finalizer.HandleSuccess(w, res)
+	}), t.middlewares...).ServeHTTP(w, r)
+}
 
+// Routes returns the routes of ControllerHTTPGen.
+func (t *ControllerHTTPGen) Routes() httper.Routes {
+	return httper.Routes{
+		{Name: "GetByID", Path: "/getbyid/{id}", Methods: []string{"GET"}, Handler: t.GetByID},
+		{Name: "UpdateByID", Path: "/updatebyid/{id}", Methods: []string{"PUT"}, Handler: t.UpdateByID},
+		{Name: "DeleteByID", Path: "/deletebyid", Methods: []string{"DELETE"}, Handler: t.DeleteByID},
+		{Name: "TestVars1", Path: "/testvars1", Handler: t.TestVars1},
+		{Name: "TestCookier", Path: "/testcookier", Handler: t.TestCookier},
+		{Name: "TestSessionner", Path: "/testsessionner", Handler: t.TestSessionner},
+		{Name: "TestRPCer", Path: "/testrpcer", Handler: t.TestRPCer},
+	}
+}
+
+// Bind the routes of ControllerHTTPGen to r.
+func (t *ControllerHTTPGen) Bind(r *mux.Router) {
+	for _, route := range t.Routes() {
+		x := r.HandleFunc(route.Path, route.Handler).Name(route.Name)
+		if len(route.Methods) > 0 {
+			x.Methods(route.Methods...)
+		}
+	}
 }
@@ -0,0 +1,126 @@
+package main
+
+// file generated by
+// github.com/mh-cbon/httper
+// do not edit
+
+import (
+	"github.com/mh-cbon/httper/lib"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// ControllerHTTPGenEmbedCall is a call recorded by a ControllerHTTPGenEmbedMock.
+type ControllerHTTPGenEmbedCall struct {
+	Method string
+	Args   []interface{}
+}
+
+// ControllerHTTPGenEmbedMock is a recording mock of ControllerHTTPGenEmbed,
+// its methods invoke the func of the same name when it is set,
+// they return zero values otherwise.
+type ControllerHTTPGenEmbedMock struct {
+	GetByIDFunc        func(urlID int) (io.Reader, error)
+	UpdateByIDFunc     func(urlID int, reqBody io.Reader) (io.Reader, error)
+	DeleteByIDFunc     func(REQid int) (io.Reader, error)
+	TestVars1Func      func(w http.ResponseWriter, r *http.Request) (io.Reader, error)
+	TestCookierFunc    func(c httper.Cookier) (io.Reader, error)
+	TestSessionnerFunc func(s httper.Sessionner) (io.Reader, error)
+	TestRPCerFunc      func(r *http.Request) (io.Reader, error)
+
+	mu    sync.Mutex
+	calls []ControllerHTTPGenEmbedCall
+}
+
+var _ ControllerHTTPGenEmbed = &ControllerHTTPGenEmbedMock{}
+
+func (mock *ControllerHTTPGenEmbedMock) record(method string, args ...interface{}) {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	mock.calls = append(mock.calls, ControllerHTTPGenEmbedCall{Method: method, Args: args})
+}
+
+// Calls returns the recorded calls.
+func (mock *ControllerHTTPGenEmbedMock) Calls() []ControllerHTTPGenEmbedCall {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	return append([]ControllerHTTPGenEmbedCall{}, mock.calls...)
+}
+
+// GetByID records its call, then invokes GetByIDFunc when it is set.
+func (mock *ControllerHTTPGenEmbedMock) GetByID(urlID int) (io.Reader, error) {
+	mock.record("GetByID", urlID)
+	if mock.GetByIDFunc != nil {
+		return mock.GetByIDFunc(urlID)
+	}
+	var ret0 io.Reader
+	var ret1 error
+	return ret0, ret1
+}
+
+// UpdateByID records its call, then invokes UpdateByIDFunc when it is set.
+func (mock *ControllerHTTPGenEmbedMock) UpdateByID(urlID int, reqBody io.Reader) (io.Reader, error) {
+	mock.record("UpdateByID", urlID, reqBody)
+	if mock.UpdateByIDFunc != nil {
+		return mock.UpdateByIDFunc(urlID, reqBody)
+	}
+	var ret0 io.Reader
+	var ret1 error
+	return ret0, ret1
+}
+
+// DeleteByID records its call, then invokes DeleteByIDFunc when it is set.
+func (mock *ControllerHTTPGenEmbedMock) DeleteByID(REQid int) (io.Reader, error) {
+	mock.record("DeleteByID", REQid)
+	if mock.DeleteByIDFunc != nil {
+		return mock.DeleteByIDFunc(REQid)
+	}
+	var ret0 io.Reader
+	var ret1 error
+	return ret0, ret1
+}
+
+// TestVars1 records its call, then invokes TestVars1Func when it is set.
+func (mock *ControllerHTTPGenEmbedMock) TestVars1(w http.ResponseWriter, r *http.Request) (io.Reader, error) {
+	mock.record("TestVars1", w, r)
+	if mock.TestVars1Func != nil {
+		return mock.TestVars1Func(w, r)
+	}
+	var ret0 io.Reader
+	var ret1 error
+	return ret0, ret1
+}
+
+// TestCookier records its call, then invokes TestCookierFunc when it is set.
+func (mock *ControllerHTTPGenEmbedMock) TestCookier(c httper.Cookier) (io.Reader, error) {
+	mock.record("TestCookier", c)
+	if mock.TestCookierFunc != nil {
+		return mock.TestCookierFunc(c)
+	}
+	var ret0 io.Reader
+	var ret1 error
+	return ret0, ret1
+}
+
+// TestSessionner records its call, then invokes TestSessionnerFunc when it is set.
+func (mock *ControllerHTTPGenEmbedMock) TestSessionner(s httper.Sessionner) (io.Reader, error) {
+	mock.record("TestSessionner", s)
+	if mock.TestSessionnerFunc != nil {
+		return mock.TestSessionnerFunc(s)
+	}
+	var ret0 io.Reader
+	var ret1 error
+	return ret0, ret1
+}
+
+// TestRPCer records its call, then invokes TestRPCerFunc when it is set.
+func (mock *ControllerHTTPGenEmbedMock) TestRPCer(r *http.Request) (io.Reader, error) {
+	mock.record("TestRPCer", r)
+	if mock.TestRPCerFunc != nil {
+		return mock.TestRPCerFunc(r)
+	}
+	var ret0 io.Reader
+	var ret1 error
+	return ret0, ret1
+}
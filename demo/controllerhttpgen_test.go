@@ -0,0 +1,318 @@
+package main
+
+// file generated by
+// github.com/mh-cbon/httper
+// do not edit
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gorilla/mux"
+	httper "github.com/mh-cbon/httper/lib"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCaseControllerHTTPGen is a request of a handler of ControllerHTTPGen.
+type testCaseControllerHTTPGen struct {
+	name    string
+	query   url.Values
+	form    url.Values
+	header  http.Header
+	cookies map[string]string
+	vars    map[string]string
+	// files are the contents of the multipart files, by name.
+	files       map[string]string
+	body        string
+	contentType string
+	// anonymous requests are not authenticated.
+	anonymous bool
+	// wantCode is the code of the expected binding error,
+	// the binding must succeed when it is empty.
+	wantCode string
+	// wantStatus is the status of the response, when it is not 0.
+	wantStatus int
+	// wantArgs checks the arguments of the single call of the method.
+	wantArgs func(t *testing.T, args []interface{})
+}
+
+// runTestControllerHTTPGen sends the request of tc to h.
+func runTestControllerHTTPGen(t *testing.T, h http.HandlerFunc, method string, tc testCaseControllerHTTPGen) *httptest.ResponseRecorder {
+	t.Helper()
+	var body io.Reader = strings.NewReader(tc.body)
+	contentType := tc.contentType
+	if len(tc.form) > 0 {
+		body = strings.NewReader(tc.form.Encode())
+		contentType = "application/x-www-form-urlencoded"
+	}
+	if len(tc.files) > 0 {
+		buf := &bytes.Buffer{}
+		mw := multipart.NewWriter(buf)
+		for k, v := range tc.form {
+			for _, s := range v {
+				mw.WriteField(k, s)
+			}
+		}
+		for k, v := range tc.files {
+			f, err := mw.CreateFormFile(k, k+".txt")
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.Write([]byte(v))
+		}
+		mw.Close()
+		body = buf
+		contentType = mw.FormDataContentType()
+	}
+	req := httptest.NewRequest(method, "/?"+tc.query.Encode(), body)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for k, v := range tc.header {
+		req.Header[k] = v
+	}
+	for k, v := range tc.cookies {
+		req.AddCookie(&http.Cookie{Name: k, Value: v})
+	}
+	if len(tc.vars) > 0 {
+		req = mux.SetURLVars(req, tc.vars)
+	}
+	w := httptest.NewRecorder()
+	h(w, req)
+	problem := &httper.Problem{}
+	if strings.HasPrefix(w.Header().Get("Content-Type"), "application/problem+json") {
+		json.Unmarshal(w.Body.Bytes(), problem)
+	}
+	if tc.wantCode != "" {
+		if w.Code != http.StatusBadRequest || problem.Code != tc.wantCode {
+			t.Fatalf("want a %v error, got %v %v", tc.wantCode, w.Code, w.Body.String())
+		}
+	} else if problem.Code == "invalid_parameter" || problem.Code == "invalid_body" {
+		t.Fatalf("unexpected binding error %v %v", w.Code, w.Body.String())
+	}
+	if tc.wantStatus != 0 && w.Code != tc.wantStatus {
+		t.Fatalf("want the status %v, got %v %v", tc.wantStatus, w.Code, w.Body.String())
+	}
+	return w
+}
+
+// checkCallsControllerHTTPGen checks the calls of the method recorded for tc,
+// it must not be invoked on an error, it must be invoked once otherwise.
+func checkCallsControllerHTTPGen(t *testing.T, calls []ControllerHTTPGenEmbedCall, w *httptest.ResponseRecorder, tc testCaseControllerHTTPGen) {
+	t.Helper()
+	if (tc.wantCode != "" || tc.wantStatus >= 400) && len(calls) > 0 {
+		t.Fatalf("the method must not be invoked on an error")
+	}
+	if (tc.wantArgs != nil || w.Code < 400) && len(calls) != 1 {
+		t.Fatalf("the method must be invoked once, got %v calls, %v %v", len(calls), w.Code, w.Body.String())
+	}
+	if tc.wantArgs != nil {
+		tc.wantArgs(t, calls[0].Args)
+	}
+}
+
+// checkArgControllerHTTPGen fails when the argument name of a call is not want.
+func checkArgControllerHTTPGen(t *testing.T, name string, got, want interface{}) {
+	t.Helper()
+	if x, ok := got.(time.Time); ok {
+		if y, ok := want.(time.Time); ok && x.Equal(y) {
+			return
+		}
+	} else if reflect.DeepEqual(got, want) {
+		return
+	}
+	t.Fatalf("want the argument %v %#v, got %#v", name, want, got)
+}
+
+// readArgControllerHTTPGen returns the content of an argument of a call,
+// a file, the first of a list of files, or a reader.
+func readArgControllerHTTPGen(t *testing.T, arg interface{}) string {
+	t.Helper()
+	if files, ok := arg.([]*multipart.FileHeader); ok && len(files) > 0 {
+		arg = files[0]
+	}
+	if fh, ok := arg.(*multipart.FileHeader); ok && fh != nil {
+		f, err := fh.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		arg = f
+	}
+	r, ok := arg.(io.Reader)
+	if !ok {
+		return ""
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+// testOptionsControllerHTTPGen returns the options of the handlers,
+// they register the named middlewares.
+func testOptionsControllerHTTPGen() []httper.Option {
+	return []httper.Option{}
+}
+
+// testAuthControllerHTTPGen returns the options authenticating the requests as principal,
+// and authorizing them.
+func testAuthControllerHTTPGen(principal interface{}) []httper.Option {
+	return []httper.Option{
+		httper.WithAuthenticator(httper.AuthenticatorFunc(func(r *http.Request) (interface{}, error) {
+			return principal, nil
+		})),
+		httper.WithAuthorizer(httper.AuthorizerFunc(func(r *http.Request, principal interface{}, roles, permissions []string) error {
+			return nil
+		})),
+	}
+}
+
+// TestControllerHTTPGenGetByID checks the binding of the parameters of *ControllerJSONGen.GetByID.
+func TestControllerHTTPGenGetByID(t *testing.T) {
+	tests := []testCaseControllerHTTPGen{
+		{name: "missing"},
+		{name: "valid", vars: map[string]string{"id": "1"}, wantArgs: func(t *testing.T, args []interface{}) {
+			checkArgControllerHTTPGen(t, "urlID", args[0], 1)
+		}},
+		{name: "malformed urlID", vars: map[string]string{"id": "x"}, wantCode: "invalid_parameter"},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mock := &ControllerHTTPGenEmbedMock{}
+			opts := testOptionsControllerHTTPGen()
+			h := NewControllerHTTPGenWithOptions(mock, opts...)
+			w := runTestControllerHTTPGen(t, h.GetByID, "GET", tc)
+			checkCallsControllerHTTPGen(t, mock.Calls(), w, tc)
+		})
+	}
+}
+
+// TestControllerHTTPGenUpdateByID checks the binding of the parameters of *ControllerJSONGen.UpdateByID.
+func TestControllerHTTPGenUpdateByID(t *testing.T) {
+	tests := []testCaseControllerHTTPGen{
+		{name: "missing"},
+		{name: "valid", vars: map[string]string{"id": "1"}, body: "x", wantArgs: func(t *testing.T, args []interface{}) {
+			checkArgControllerHTTPGen(t, "reqBody", readArgControllerHTTPGen(t, args[1]), "x")
+			checkArgControllerHTTPGen(t, "urlID", args[0], 1)
+		}},
+		{name: "malformed urlID", vars: map[string]string{"id": "x"}, wantCode: "invalid_parameter"},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mock := &ControllerHTTPGenEmbedMock{}
+			opts := testOptionsControllerHTTPGen()
+			h := NewControllerHTTPGenWithOptions(mock, opts...)
+			w := runTestControllerHTTPGen(t, h.UpdateByID, "PUT", tc)
+			checkCallsControllerHTTPGen(t, mock.Calls(), w, tc)
+		})
+	}
+}
+
+// TestControllerHTTPGenDeleteByID checks the binding of the parameters of *ControllerJSONGen.DeleteByID.
+func TestControllerHTTPGenDeleteByID(t *testing.T) {
+	tests := []testCaseControllerHTTPGen{
+		{name: "missing"},
+		{name: "valid", query: url.Values{"id": {"1"}}, wantArgs: func(t *testing.T, args []interface{}) {
+			checkArgControllerHTTPGen(t, "REQid", args[0], 1)
+		}},
+		{name: "malformed REQid", query: url.Values{"id": {"x"}}, wantCode: "invalid_parameter"},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mock := &ControllerHTTPGenEmbedMock{}
+			opts := testOptionsControllerHTTPGen()
+			h := NewControllerHTTPGenWithOptions(mock, opts...)
+			w := runTestControllerHTTPGen(t, h.DeleteByID, "DELETE", tc)
+			checkCallsControllerHTTPGen(t, mock.Calls(), w, tc)
+		})
+	}
+}
+
+// TestControllerHTTPGenTestVars1 checks the binding of the parameters of *ControllerJSONGen.TestVars1.
+func TestControllerHTTPGenTestVars1(t *testing.T) {
+	tests := []testCaseControllerHTTPGen{
+		{name: "missing"},
+		{name: "valid", wantArgs: func(t *testing.T, args []interface{}) {
+		}},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mock := &ControllerHTTPGenEmbedMock{}
+			opts := testOptionsControllerHTTPGen()
+			h := NewControllerHTTPGenWithOptions(mock, opts...)
+			w := runTestControllerHTTPGen(t, h.TestVars1, "GET", tc)
+			checkCallsControllerHTTPGen(t, mock.Calls(), w, tc)
+		})
+	}
+}
+
+// TestControllerHTTPGenTestCookier checks the binding of the parameters of *ControllerJSONGen.TestCookier.
+func TestControllerHTTPGenTestCookier(t *testing.T) {
+	tests := []testCaseControllerHTTPGen{
+		{name: "missing"},
+		{name: "valid", wantArgs: func(t *testing.T, args []interface{}) {
+		}},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mock := &ControllerHTTPGenEmbedMock{}
+			opts := testOptionsControllerHTTPGen()
+			h := NewControllerHTTPGenWithOptions(mock, opts...)
+			w := runTestControllerHTTPGen(t, h.TestCookier, "GET", tc)
+			checkCallsControllerHTTPGen(t, mock.Calls(), w, tc)
+		})
+	}
+}
+
+// TestControllerHTTPGenTestSessionner checks the binding of the parameters of *ControllerJSONGen.TestSessionner.
+func TestControllerHTTPGenTestSessionner(t *testing.T) {
+	tests := []testCaseControllerHTTPGen{
+		{name: "missing"},
+		{name: "valid", wantArgs: func(t *testing.T, args []interface{}) {
+		}},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mock := &ControllerHTTPGenEmbedMock{}
+			opts := testOptionsControllerHTTPGen()
+			h := NewControllerHTTPGenWithOptions(mock, opts...)
+			w := runTestControllerHTTPGen(t, h.TestSessionner, "GET", tc)
+			checkCallsControllerHTTPGen(t, mock.Calls(), w, tc)
+		})
+	}
+}
+
+// TestControllerHTTPGenTestRPCer checks the binding of the parameters of *ControllerJSONGen.TestRPCer.
+func TestControllerHTTPGenTestRPCer(t *testing.T) {
+	tests := []testCaseControllerHTTPGen{
+		{name: "missing"},
+		{name: "valid", wantArgs: func(t *testing.T, args []interface{}) {
+		}},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mock := &ControllerHTTPGenEmbedMock{}
+			opts := testOptionsControllerHTTPGen()
+			h := NewControllerHTTPGenWithOptions(mock, opts...)
+			w := runTestControllerHTTPGen(t, h.TestRPCer, "GET", tc)
+			checkCallsControllerHTTPGen(t, mock.Calls(), w, tc)
+		})
+	}
+}
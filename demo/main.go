@@ -7,6 +7,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/gorilla/mux"
 	httper "github.com/mh-cbon/httper/lib"
 )
 
@@ -14,7 +15,8 @@ import (
 //go:generate channeler TomatesGen:TomatesSyncGen
 
 //go:generate jsoner -mode gorilla *Controller:ControllerJSONGen
-//go:generate httper -mode gorilla *ControllerJSONGen:ControllerHTTPGen
+//go:generate httper -mode gorilla -tests -interface *ControllerJSONGen:ControllerHTTPGen
+//go:generate httper -mode gorilla -client *ControllerJSONGen:ControllerClientGen
 
 func main() {
 
@@ -23,8 +25,10 @@ func main() {
 
 	jsoner := NewControllerJSONGen(NewController(backend), nil)
 	httper := NewControllerHTTPGen(jsoner, nil)
-	// public views
-	http.HandleFunc("/", httper.GetByID)
+
+	router := mux.NewRouter()
+	httper.Bind(router)
+	http.Handle("/", router)
 
 	go func() {
 		log.Fatal(http.ListenAndServe(":8080", nil))
@@ -32,12 +36,12 @@ func main() {
 
 	time.Sleep(1 * time.Millisecond)
 
-	req, err := http.Get("http://localhost:8080/?id=0")
+	client := NewControllerClientGen("http://localhost:8080", nil)
+	res, err := client.GetByID(0)
 	if err != nil {
 		panic(err)
 	}
-	defer req.Body.Close()
-	io.Copy(os.Stdout, req.Body)
+	io.Copy(os.Stdout, res)
 
 }
 
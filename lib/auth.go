@@ -0,0 +1,128 @@
+package httper
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Authenticator authenticates the requests,
+// it returns the principal of r, or an error.
+// An error which is not an HTTPError is handled as an http 401 Error.
+type Authenticator interface {
+	Authenticate(r *http.Request) (interface{}, error)
+}
+
+// AuthenticatorFunc is a func Authenticator.
+type AuthenticatorFunc func(r *http.Request) (interface{}, error)
+
+// Authenticate r.
+func (f AuthenticatorFunc) Authenticate(r *http.Request) (interface{}, error) {
+	return f(r)
+}
+
+// Unauthorized constructs an http 401 Error.
+func Unauthorized(message string, err error) *Error {
+	return NewError(http.StatusUnauthorized, "unauthorized", message, err)
+}
+
+// Forbidden constructs an http 403 Error.
+func Forbidden(message string, err error) *Error {
+	return NewError(http.StatusForbidden, "forbidden", message, err)
+}
+
+// Authenticate returns the principal of r authenticated by a,
+// it fails with an http 401 Error when a is nil or when it returns no principal.
+func Authenticate(a Authenticator, r *http.Request) (interface{}, error) {
+	if a == nil {
+		return nil, Unauthorized("authentication required", errors.New("no authenticator"))
+	}
+	principal, err := a.Authenticate(r)
+	if err != nil {
+		var x HTTPError
+		if errors.As(err, &x) {
+			return nil, err
+		}
+		return nil, Unauthorized("authentication required", err)
+	}
+	if principal == nil {
+		return nil, Unauthorized("authentication required", nil)
+	}
+	return principal, nil
+}
+
+// Authorizer authorizes the principal of a request
+// for the roles and the permissions required by a handler.
+// An error which is not an HTTPError is handled as an http 403 Error.
+type Authorizer interface {
+	Authorize(r *http.Request, principal interface{}, roles, permissions []string) error
+}
+
+// AuthorizerFunc is a func Authorizer.
+type AuthorizerFunc func(r *http.Request, principal interface{}, roles, permissions []string) error
+
+// Authorize the principal.
+func (f AuthorizerFunc) Authorize(r *http.Request, principal interface{}, roles, permissions []string) error {
+	return f(r, principal, roles, permissions)
+}
+
+// Roler is a principal having roles.
+type Roler interface {
+	HasRole(role string) bool
+}
+
+// Permissioner is a principal having permissions.
+type Permissioner interface {
+	HasPermission(permission string) bool
+}
+
+// DefaultAuthorizer authorizes a principal having one of the roles,
+// and all the permissions, it must implement Roler and Permissioner to be checked.
+type DefaultAuthorizer struct{}
+
+// Authorize the principal.
+func (a DefaultAuthorizer) Authorize(r *http.Request, principal interface{}, roles, permissions []string) error {
+	if len(roles) > 0 {
+		roler, ok := principal.(Roler)
+		if !ok {
+			return Forbidden("forbidden", errors.New("the principal has no roles"))
+		}
+		allowed := false
+		for _, role := range roles {
+			if roler.HasRole(role) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return Forbidden("forbidden", errors.New("the principal has none of the roles"))
+		}
+	}
+	if len(permissions) > 0 {
+		permissioner, ok := principal.(Permissioner)
+		if !ok {
+			return Forbidden("forbidden", errors.New("the principal has no permissions"))
+		}
+		for _, permission := range permissions {
+			if !permissioner.HasPermission(permission) {
+				return Forbidden("forbidden", errors.New("the principal has not the permission "+permission))
+			}
+		}
+	}
+	return nil
+}
+
+// Authorize the principal with a,
+// it fails with an http 403 Error when a is nil.
+func Authorize(a Authorizer, r *http.Request, principal interface{}, roles, permissions []string) error {
+	if a == nil {
+		return Forbidden("forbidden", errors.New("no authorizer"))
+	}
+	if err := a.Authorize(r, principal, roles, permissions); err != nil {
+		var x HTTPError
+		if errors.As(err, &x) {
+			return err
+		}
+		return Forbidden("forbidden", err)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package httper
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthenticate(t *testing.T) {
+	tests := []struct {
+		name       string
+		a          Authenticator
+		wantStatus int
+	}{
+		{"principal", AuthenticatorFunc(func(r *http.Request) (interface{}, error) { return "bob", nil }), 0},
+		{"no authenticator", nil, http.StatusUnauthorized},
+		{"no principal", AuthenticatorFunc(func(r *http.Request) (interface{}, error) { return nil, nil }), http.StatusUnauthorized},
+		{"error", AuthenticatorFunc(func(r *http.Request) (interface{}, error) { return nil, errors.New("bad token") }), http.StatusUnauthorized},
+		{"http error", AuthenticatorFunc(func(r *http.Request) (interface{}, error) {
+			return nil, NewError(http.StatusServiceUnavailable, "down", "down", nil)
+		}), http.StatusServiceUnavailable},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			principal, err := Authenticate(tc.a, httptest.NewRequest("GET", "/", nil))
+			if tc.wantStatus == 0 {
+				if err != nil || principal != "bob" {
+					t.Fatalf("want the principal bob, got %v %v", principal, err)
+				}
+				return
+			}
+			var httpErr HTTPError
+			if !errors.As(err, &httpErr) || httpErr.StatusCode() != tc.wantStatus {
+				t.Fatalf("want an http %v error, got %v", tc.wantStatus, err)
+			}
+			if principal != nil {
+				t.Fatalf("want no principal, got %v", principal)
+			}
+		})
+	}
+}
+
+// authPrincipal is a principal of roles and permissions.
+type authPrincipal struct {
+	roles       map[string]bool
+	permissions map[string]bool
+}
+
+func (p authPrincipal) HasRole(role string) bool             { return p.roles[role] }
+func (p authPrincipal) HasPermission(permission string) bool { return p.permissions[permission] }
+
+func TestAuthorize(t *testing.T) {
+	admin := authPrincipal{
+		roles:       map[string]bool{"admin": true},
+		permissions: map[string]bool{"items.read": true, "items.write": true},
+	}
+	tests := []struct {
+		name        string
+		a           Authorizer
+		principal   interface{}
+		roles       []string
+		permissions []string
+		wantStatus  int
+	}{
+		{"nothing required", DefaultAuthorizer{}, "bob", nil, nil, 0},
+		{"one of the roles", DefaultAuthorizer{}, admin, []string{"editor", "admin"}, nil, 0},
+		{"none of the roles", DefaultAuthorizer{}, admin, []string{"editor"}, nil, http.StatusForbidden},
+		{"no roles", DefaultAuthorizer{}, "bob", []string{"admin"}, nil, http.StatusForbidden},
+		{"all the permissions", DefaultAuthorizer{}, admin, nil, []string{"items.read", "items.write"}, 0},
+		{"missing permission", DefaultAuthorizer{}, admin, nil, []string{"items.read", "items.delete"}, http.StatusForbidden},
+		{"no permissions", DefaultAuthorizer{}, "bob", nil, []string{"items.read"}, http.StatusForbidden},
+		{"no authorizer", nil, admin, nil, nil, http.StatusForbidden},
+		{"error", AuthorizerFunc(func(r *http.Request, principal interface{}, roles, permissions []string) error {
+			return errors.New("denied")
+		}), admin, nil, nil, http.StatusForbidden},
+		{"http error", AuthorizerFunc(func(r *http.Request, principal interface{}, roles, permissions []string) error {
+			return NotFound("not_found", "not found", nil)
+		}), admin, nil, nil, http.StatusNotFound},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := Authorize(tc.a, httptest.NewRequest("GET", "/", nil), tc.principal, tc.roles, tc.permissions)
+			if tc.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("want no error, got %v", err)
+				}
+				return
+			}
+			var httpErr HTTPError
+			if !errors.As(err, &httpErr) || httpErr.StatusCode() != tc.wantStatus {
+				t.Fatalf("want an http %v error, got %v", tc.wantStatus, err)
+			}
+		})
+	}
+}
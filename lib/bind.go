@@ -0,0 +1,88 @@
+package httper
+
+import (
+	"errors"
+	"mime/multipart"
+	"reflect"
+	"strings"
+)
+
+// BindTag is the struct tag of the fields filled by BindData,
+// its value is the prefix of the data followed by its name, `httper:"get,page"`.
+var BindTag = "httper"
+
+// BindData fills the tagged fields of the struct v with the data of d,
+// it returns a ParamError when a value can not be converted.
+func BindData(d Dataer, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("BindData requires a non nil pointer")
+	}
+	return bindData(d, rv.Elem())
+}
+
+func bindData(d Dataer, v reflect.Value) error {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return errors.New("BindData requires a struct, got " + v.Type().String())
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get(BindTag)
+		if tag == "" || tag == "-" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if field.Anonymous && tag == "" && ft.Kind() == reflect.Struct {
+				if err := bindData(d, v.Field(i)); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		prefix, name := ParseBindTag(tag, field.Name)
+		fv := v.Field(i)
+		if field.Type == fileHeaderType || field.Type == reflect.SliceOf(fileHeaderType) {
+			if files, ok := d.GetAny(prefix, name).([]*multipart.FileHeader); ok && len(files) > 0 {
+				if field.Type == fileHeaderType {
+					fv.Set(reflect.ValueOf(files[0]))
+				} else {
+					fv.Set(reflect.ValueOf(files))
+				}
+			}
+			continue
+		}
+		var values []string
+		if fv.Kind() == reflect.Slice && !reflect.PtrTo(field.Type).Implements(textUnmarshalerType) {
+			values = d.GetAll(prefix, name)
+		} else if s := d.Get(prefix, name); s != "" {
+			values = []string{s}
+		}
+		if err := setValues(fv, values); err != nil {
+			return ParamError(prefix, name, err)
+		}
+	}
+	return nil
+}
+
+// ParseBindTag returns the prefix and the name of a BindTag,
+// the name defaults to the lower cased fieldName.
+func ParseBindTag(tag, fieldName string) (string, string) {
+	parts := strings.SplitN(tag, ",", 2)
+	prefix := strings.TrimSpace(parts[0])
+	name := strings.ToLower(fieldName)
+	if len(parts) > 1 && strings.TrimSpace(parts[1]) != "" {
+		name = strings.TrimSpace(parts[1])
+	}
+	return prefix, name
+}
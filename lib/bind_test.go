@@ -0,0 +1,124 @@
+package httper
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// mapDataer is a Dataer of the values of "prefix:name".
+type mapDataer map[string][]string
+
+func (d mapDataer) Get(prefix, name string) string {
+	if v := d[prefix+":"+name]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+func (d mapDataer) GetAll(prefix, name string) []string {
+	return d[prefix+":"+name]
+}
+
+func (d mapDataer) GetAny(prefix, name string) interface{} {
+	return d.GetAll(prefix, name)
+}
+
+func TestParseBindTag(t *testing.T) {
+	tests := []struct {
+		tag        string
+		wantPrefix string
+		wantName   string
+	}{
+		{"get,page", "get", "page"},
+		{"get", "get", "field"},
+		{"get,", "get", "field"},
+		{" header , X-Token ", "header", "X-Token"},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.tag, func(t *testing.T) {
+			prefix, name := ParseBindTag(tc.tag, "Field")
+			if prefix != tc.wantPrefix || name != tc.wantName {
+				t.Fatalf("want %q %q, got %q %q", tc.wantPrefix, tc.wantName, prefix, name)
+			}
+		})
+	}
+}
+
+type BindEmbedded struct {
+	Sort string `httper:"get,sort"`
+}
+
+type bindStruct struct {
+	*BindEmbedded
+	Page    int           `httper:"get,page"`
+	Tags    []string      `httper:"get,tag"`
+	IDs     []int         `httper:"get,id"`
+	Ratio   *float64      `httper:"get,ratio"`
+	Active  bool          `httper:"get"`
+	Wait    time.Duration `httper:"header,X-Wait"`
+	Skipped string        `httper:"-"`
+	Other   string
+	hidden  string `httper:"get,hidden"`
+}
+
+func TestBindData(t *testing.T) {
+	ratio := 1.5
+	tests := []struct {
+		name     string
+		data     mapDataer
+		want     bindStruct
+		wantErr  string
+		wantName string
+	}{
+		{"empty", mapDataer{}, bindStruct{BindEmbedded: &BindEmbedded{}}, "", ""},
+		{"values", mapDataer{
+			"get:page":      {"2"},
+			"get:tag":       {"a", "b"},
+			"get:id":        {"1", "2"},
+			"get:ratio":     {"1.5"},
+			"get:active":    {"true"},
+			"get:sort":      {"asc"},
+			"header:X-Wait": {"1s"},
+			"get:Skipped":   {"x"},
+			"get:hidden":    {"x"},
+		}, bindStruct{BindEmbedded: &BindEmbedded{Sort: "asc"}, Page: 2, Tags: []string{"a", "b"}, IDs: []int{1, 2},
+			Ratio: &ratio, Active: true, Wait: time.Second}, "", ""},
+		{"malformed int", mapDataer{"get:page": {"x"}}, bindStruct{}, "invalid_parameter", `invalid get parameter "page"`},
+		{"malformed slice", mapDataer{"get:id": {"1", "x"}}, bindStruct{}, "invalid_parameter", `invalid get parameter "id"`},
+		{"malformed duration", mapDataer{"header:X-Wait": {"x"}}, bindStruct{}, "invalid_parameter", `invalid header parameter "X-Wait"`},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := bindStruct{}
+			err := BindData(tc.data, &got)
+			if tc.wantErr != "" {
+				var httpErr HTTPError
+				if !errors.As(err, &httpErr) || httpErr.StatusCode() != http.StatusBadRequest ||
+					httpErr.Code() != tc.wantErr || httpErr.PublicMessage() != tc.wantName {
+					t.Fatalf("want a %v error %q, got %v", tc.wantErr, tc.wantName, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("want %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestBindDataNotAStruct(t *testing.T) {
+	v := 1
+	for _, x := range []interface{}{bindStruct{}, (*bindStruct)(nil), &v} {
+		if err := BindData(mapDataer{}, x); err == nil {
+			t.Fatalf("want an error binding %T", x)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package httper
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxBodySize is the size limit of the request bodies decoded by a ContentBodyDecoder.
+var DefaultMaxBodySize int64 = 10 << 20
+
+// DefaultMaxMemory is the memory limit to parse the multipart bodies,
+// the remaining parts are stored on disk.
+var DefaultMaxMemory int64 = 32 << 20
+
+// BodyDecoder decodes the request bodies.
+type BodyDecoder interface {
+	Decode(w http.ResponseWriter, r *http.Request, v interface{}) error
+}
+
+// ContentBodyDecoder decodes the request body according to its Content-Type,
+// it supports json, and for a struct, urlencoded and multipart forms.
+// A body without Content-Type is decoded as json.
+type ContentBodyDecoder struct {
+	// MaxSize of the body, DefaultMaxBodySize when it is 0.
+	MaxSize int64
+	// MaxMemory to parse a multipart body, DefaultMaxMemory when it is 0.
+	MaxMemory int64
+}
+
+// Decode the body of r into v, it returns an http 415 Error
+// for an unsupported Content-Type, an http 400 Error when it fails to decode.
+func (d ContentBodyDecoder) Decode(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	maxSize := d.MaxSize
+	if maxSize == 0 {
+		maxSize = DefaultMaxBodySize
+	}
+	maxMemory := d.MaxMemory
+	if maxMemory == 0 {
+		maxMemory = DefaultMaxMemory
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+
+	contentType := r.Header.Get("Content-Type")
+	mediaType := "application/json"
+	if contentType != "" {
+		var err error
+		mediaType, _, err = mime.ParseMediaType(contentType)
+		if err != nil {
+			return UnsupportedMediaType(contentType)
+		}
+	}
+
+	isJSON := mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+	isForm := mediaType == "application/x-www-form-urlencoded" || mediaType == "multipart/form-data"
+	if isJSON {
+		if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+			return BodyError(err)
+		}
+		return nil
+	}
+	if !isForm || !isStructPtr(v) {
+		return UnsupportedMediaType(contentType)
+	}
+
+	var files map[string][]*multipart.FileHeader
+	if mediaType == "multipart/form-data" {
+		if err := r.ParseMultipartForm(maxMemory); err != nil {
+			return BodyError(err)
+		}
+		files = r.MultipartForm.File
+	} else if err := r.ParseForm(); err != nil {
+		return BodyError(err)
+	}
+	if err := decodeForm(reflect.ValueOf(v).Elem(), r.PostForm, files); err != nil {
+		return BodyError(err)
+	}
+	return nil
+}
+
+// SetMaxMemory to parse the multipart forms.
+func (d *ContentBodyDecoder) SetMaxMemory(n int64) {
+	d.MaxMemory = n
+}
+
+// UnsupportedMediaType constructs the http 415 Error of a request body of contentType.
+func UnsupportedMediaType(contentType string) *Error {
+	return NewError(http.StatusUnsupportedMediaType, "unsupported_media_type",
+		fmt.Sprintf("unsupported content type %q", contentType), nil)
+}
+
+func isStructPtr(v interface{}) bool {
+	t := reflect.TypeOf(v)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return false
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+var fileHeaderType = reflect.TypeOf(&multipart.FileHeader{})
+
+// decodeForm sets the fields of the struct v from the form values and files,
+// a field is named by its form tag, then its json tag, then its name.
+func decodeForm(v reflect.Value, values map[string][]string, files map[string][]*multipart.FileHeader) error {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := fieldName(field, "form")
+		if name == "-" {
+			continue
+		}
+		ft := field.Type
+		if field.Anonymous && name == "" {
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if err := decodeForm(v.Field(i), values, files); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if ft == fileHeaderType {
+			if f := files[name]; len(f) > 0 {
+				v.Field(i).Set(reflect.ValueOf(f[0]))
+			}
+			continue
+		}
+		if ft == reflect.SliceOf(fileHeaderType) {
+			if f := files[name]; len(f) > 0 {
+				v.Field(i).Set(reflect.ValueOf(f))
+			}
+			continue
+		}
+		if vals, ok := values[name]; ok {
+			if err := setValues(v.Field(i), vals); err != nil {
+				return fmt.Errorf("field %q: %v", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// fieldName returns the name of field in the tag key,
+// then in the json tag.
+func fieldName(field reflect.StructField, key string) string {
+	if name := strings.Split(field.Tag.Get(key), ",")[0]; name != "" {
+		return name
+	}
+	return strings.Split(field.Tag.Get("json"), ",")[0]
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// setValues converts the strings values into v.
+func setValues(v reflect.Value, values []string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return setValues(v.Elem(), values)
+	}
+	if reflect.PtrTo(v.Type()).Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(values[0]))
+	}
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		s := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, value := range values {
+			if err := setValues(s.Index(i), []string{value}); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	}
+	return setValue(v, values[0])
+}
+
+func setValue(v reflect.Value, value string) error {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(value)
+		if err == nil {
+			v.SetInt(int64(d))
+		}
+		return err
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Slice:
+		v.SetBytes([]byte(value))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	default:
+		return errors.New("unsupported type " + v.Type().String())
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package httper
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type bodyForm struct {
+	Name   string                `form:"name"`
+	Tags   []string              `form:"tags"`
+	N      int                   `json:"n"`
+	Wait   time.Duration         `form:"wait"`
+	Avatar *multipart.FileHeader `form:"avatar"`
+}
+
+// testMultipartBody returns a multipart body of the fields and of a file avatar.
+func testMultipartBody(t *testing.T, fields map[string]string) (io.Reader, string) {
+	var b bytes.Buffer
+	mw := multipart.NewWriter(&b)
+	for k, v := range fields {
+		mw.WriteField(k, v)
+	}
+	fw, err := mw.CreateFormFile("avatar", "avatar.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write([]byte("png"))
+	mw.Close()
+	return &b, mw.FormDataContentType()
+}
+
+func TestContentBodyDecoder(t *testing.T) {
+	multipartReader, multipartType := testMultipartBody(t, map[string]string{"name": "x", "n": "3"})
+	tests := []struct {
+		name        string
+		decoder     ContentBodyDecoder
+		body        io.Reader
+		contentType string
+		target      func() interface{}
+		wantStatus  int
+		want        bodyForm
+	}{
+		{name: "json", body: strings.NewReader(`{"Name":"x","n":3}`), contentType: "application/json",
+			want: bodyForm{Name: "x", N: 3}},
+		{name: "json suffix", body: strings.NewReader(`{"n":3}`), contentType: "application/problem+json",
+			want: bodyForm{N: 3}},
+		{name: "no content type", body: strings.NewReader(`{"n":3}`), want: bodyForm{N: 3}},
+		{name: "malformed json", body: strings.NewReader(`{`), contentType: "application/json",
+			wantStatus: http.StatusBadRequest},
+		{name: "empty json", body: strings.NewReader(``), contentType: "application/json",
+			wantStatus: http.StatusBadRequest},
+		{name: "urlencoded", body: strings.NewReader("name=x&tags=a&tags=b&n=3&wait=1s"),
+			contentType: "application/x-www-form-urlencoded",
+			want:        bodyForm{Name: "x", Tags: []string{"a", "b"}, N: 3, Wait: time.Second}},
+		{name: "malformed urlencoded value", body: strings.NewReader("n=x"),
+			contentType: "application/x-www-form-urlencoded", wantStatus: http.StatusBadRequest},
+		{name: "multipart", body: multipartReader, contentType: multipartType, want: bodyForm{Name: "x", N: 3}},
+		{name: "unsupported", body: strings.NewReader("<x/>"), contentType: "application/xml",
+			wantStatus: http.StatusUnsupportedMediaType},
+		{name: "invalid content type", body: strings.NewReader("{}"), contentType: "application/",
+			wantStatus: http.StatusUnsupportedMediaType},
+		{name: "form into a non struct", body: strings.NewReader("a=b"),
+			contentType: "application/x-www-form-urlencoded", wantStatus: http.StatusUnsupportedMediaType,
+			target: func() interface{} { return &map[string]string{} }},
+		{name: "json too large", decoder: ContentBodyDecoder{MaxSize: 10},
+			body: strings.NewReader(`{"Name":"` + strings.Repeat("a", 100) + `"}`), contentType: "application/json",
+			wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "urlencoded too large", decoder: ContentBodyDecoder{MaxSize: 10},
+			body: strings.NewReader("name=" + strings.Repeat("a", 100)), contentType: "application/x-www-form-urlencoded",
+			wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "json within the limit", decoder: ContentBodyDecoder{MaxSize: 100},
+			body: strings.NewReader(`{"n":3}`), contentType: "application/json", want: bodyForm{N: 3}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/", tc.body)
+			if tc.contentType != "" {
+				r.Header.Set("Content-Type", tc.contentType)
+			}
+			var got bodyForm
+			var target interface{} = &got
+			if tc.target != nil {
+				target = tc.target()
+			}
+			err := tc.decoder.Decode(httptest.NewRecorder(), r, target)
+			if tc.wantStatus != 0 {
+				httpErr, ok := err.(HTTPError)
+				if !ok || httpErr.StatusCode() != tc.wantStatus {
+					t.Fatalf("want an http %v error, got %v", tc.wantStatus, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.name == "multipart" {
+				if got.Avatar == nil || got.Avatar.Filename != "avatar.png" {
+					t.Fatalf("want the avatar file, got %+v", got.Avatar)
+				}
+				got.Avatar = nil
+			}
+			if got.Name != tc.want.Name || got.N != tc.want.N || got.Wait != tc.want.Wait ||
+				strings.Join(got.Tags, ",") != strings.Join(tc.want.Tags, ",") {
+				t.Fatalf("want %+v, got %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestContentBodyDecoderMultipartTooLarge(t *testing.T) {
+	body, contentType := testMultipartBody(t, map[string]string{"name": strings.Repeat("a", 1000)})
+	r := httptest.NewRequest("POST", "/", body)
+	r.Header.Set("Content-Type", contentType)
+	var got bodyForm
+	err := ContentBodyDecoder{MaxSize: 100}.Decode(httptest.NewRecorder(), r, &got)
+	if httpErr, ok := err.(HTTPError); !ok || httpErr.StatusCode() != http.StatusRequestEntityTooLarge {
+		t.Fatalf("want an http 413 error, got %v", err)
+	}
+}
@@ -0,0 +1,321 @@
+package httper
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// Client sends the requests of a generated http client.
+type Client struct {
+	BaseURL string
+	HTTP    *http.Client
+}
+
+// NewClient constructs a Client of baseURL,
+// when hc is nil it uses http.DefaultClient.
+func NewClient(baseURL string, hc *http.Client) *Client {
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	return &Client{BaseURL: strings.TrimSuffix(baseURL, "/"), HTTP: hc}
+}
+
+// ClientError is returned by a Client when the response status is not a success.
+type ClientError struct {
+	StatusCode int
+	Body       []byte
+	// Problem is decoded from an application/problem+json response.
+	Problem *Problem
+}
+
+func (e *ClientError) Error() string {
+	if e.Problem != nil && e.Problem.Detail != "" {
+		return fmt.Sprintf("%v %v: %v", e.StatusCode, http.StatusText(e.StatusCode), e.Problem.Detail)
+	}
+	return fmt.Sprintf("%v %v: %s", e.StatusCode, http.StatusText(e.StatusCode), bytes.TrimSpace(e.Body))
+}
+
+// ClientRequest is a request to send with a Client.
+type ClientRequest struct {
+	Context context.Context
+	Method  string
+	Path    string
+	Vars    []string
+	Query   url.Values
+	Form    url.Values
+	Cookies []*http.Cookie
+	Header  http.Header
+	// Files are sent with the Form as a multipart form.
+	Files []ClientFile
+	// Body is sent as is when it is an io.Reader,
+	// otherwise it is encoded to json.
+	Body interface{}
+}
+
+// NewClientRequest constructs a ClientRequest.
+func NewClientRequest(method, path string) *ClientRequest {
+	return &ClientRequest{
+		Method: method,
+		Path:   path,
+		Query:  url.Values{},
+		Form:   url.Values{},
+		Header: http.Header{},
+	}
+}
+
+// SetVar sets the route variable name.
+func (r *ClientRequest) SetVar(name string, v interface{}) {
+	values := FormatValues(v)
+	if len(values) > 0 {
+		r.Vars = append(r.Vars, name, values[0])
+	} else {
+		r.Vars = append(r.Vars, name, "")
+	}
+}
+
+// AddQuery adds the values of v to the query.
+func (r *ClientRequest) AddQuery(name string, v interface{}) {
+	for _, s := range FormatValues(v) {
+		r.Query.Add(name, s)
+	}
+}
+
+// AddForm adds the values of v to the form.
+func (r *ClientRequest) AddForm(name string, v interface{}) {
+	for _, s := range FormatValues(v) {
+		r.Form.Add(name, s)
+	}
+}
+
+// AddHeader adds the values of v to the header name.
+func (r *ClientRequest) AddHeader(name string, v interface{}) {
+	for _, s := range FormatValues(v) {
+		r.Header.Add(name, s)
+	}
+}
+
+// AddCookie adds the values of v as cookies.
+func (r *ClientRequest) AddCookie(name string, v interface{}) {
+	for _, s := range FormatValues(v) {
+		r.Cookies = append(r.Cookies, &http.Cookie{Name: name, Value: s})
+	}
+}
+
+// ClientFile is a file of a ClientRequest.
+type ClientFile struct {
+	Name     string
+	Filename string
+	Content  io.Reader
+}
+
+// AddFile adds the files of v, it is an io.Reader,
+// a *multipart.FileHeader or a []*multipart.FileHeader.
+func (r *ClientRequest) AddFile(name string, v interface{}) error {
+	switch x := v.(type) {
+	case *multipart.FileHeader:
+		if x == nil {
+			return nil
+		}
+		f, err := x.Open()
+		if err != nil {
+			return err
+		}
+		r.Files = append(r.Files, ClientFile{Name: name, Filename: x.Filename, Content: f})
+	case []*multipart.FileHeader:
+		for _, f := range x {
+			if err := r.AddFile(name, f); err != nil {
+				return err
+			}
+		}
+	case io.Reader:
+		if x != nil {
+			r.Files = append(r.Files, ClientFile{Name: name, Filename: name, Content: x})
+		}
+	}
+	return nil
+}
+
+// Do sends the request, it returns a *ClientError
+// when the response status is not a success.
+func (c *Client) Do(req *ClientRequest) (*http.Response, error) {
+	u, err := Route{Path: req.Path}.URL(req.Vars...)
+	if err != nil {
+		return nil, err
+	}
+	u.RawQuery = req.Query.Encode()
+
+	var body io.Reader
+	contentType := ""
+	if len(req.Files) > 0 {
+		b, ct, encErr := multipartBody(req.Form, req.Files)
+		if encErr != nil {
+			return nil, encErr
+		}
+		body = b
+		contentType = ct
+	} else if len(req.Form) > 0 {
+		body = strings.NewReader(req.Form.Encode())
+		contentType = "application/x-www-form-urlencoded"
+	} else if r, ok := req.Body.(io.Reader); ok {
+		body = r
+	} else if req.Body != nil {
+		b, encErr := json.Marshal(req.Body)
+		if encErr != nil {
+			return nil, encErr
+		}
+		body = bytes.NewReader(b)
+		contentType = "application/json"
+	}
+
+	ctx := req.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	r, err := http.NewRequestWithContext(ctx, req.Method, c.BaseURL+u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range req.Header {
+		for _, v := range values {
+			r.Header.Add(name, v)
+		}
+	}
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	for _, cookie := range req.Cookies {
+		r.AddCookie(cookie)
+	}
+
+	res, err := c.HTTP.Do(r)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		defer res.Body.Close()
+		b, _ := ioutil.ReadAll(res.Body)
+		clientErr := &ClientError{StatusCode: res.StatusCode, Body: b}
+		if strings.HasPrefix(res.Header.Get("Content-Type"), "application/problem+json") {
+			problem := &Problem{}
+			if json.Unmarshal(b, problem) == nil {
+				clientErr.Problem = problem
+			}
+		}
+		return nil, clientErr
+	}
+	return res, nil
+}
+
+func multipartBody(form url.Values, files []ClientFile) (io.Reader, string, error) {
+	var b bytes.Buffer
+	mw := multipart.NewWriter(&b)
+	for name, values := range form {
+		for _, v := range values {
+			if err := mw.WriteField(name, v); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+	for _, f := range files {
+		fw, err := mw.CreateFormFile(f.Name, f.Filename)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := io.Copy(fw, f.Content); err != nil {
+			return nil, "", err
+		}
+		if c, ok := f.Content.(io.Closer); ok {
+			c.Close()
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, "", err
+	}
+	return &b, mw.FormDataContentType(), nil
+}
+
+// Read the response body.
+func (c *Client) Read(res *http.Response) (io.Reader, error) {
+	defer res.Body.Close()
+	var b bytes.Buffer
+	_, err := io.Copy(&b, res.Body)
+	return &b, err
+}
+
+// Decode the json response body into out,
+// when there are multiple out values, the body is decoded as a json array.
+func (c *Client) Decode(res *http.Response, out ...interface{}) error {
+	defer res.Body.Close()
+	if len(out) == 0 {
+		_, err := io.Copy(ioutil.Discard, res.Body)
+		return err
+	}
+	if len(out) == 1 {
+		return json.NewDecoder(res.Body).Decode(out[0])
+	}
+	var values []json.RawMessage
+	if err := json.NewDecoder(res.Body).Decode(&values); err != nil {
+		return err
+	}
+	if len(values) != len(out) {
+		return fmt.Errorf("expected %v values, got %v", len(out), len(values))
+	}
+	for i, v := range values {
+		if err := json.Unmarshal(v, out[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FormatValues formats v to strings,
+// slices and arrays produce a value per item.
+func FormatValues(v interface{}) []string {
+	if v == nil {
+		return nil
+	}
+	if _, ok := v.([]byte); ok {
+		return []string{formatValue(v)}
+	}
+	rv := reflect.ValueOf(v)
+	if _, ok := v.(encoding.TextMarshaler); !ok {
+		if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+			ret := []string{}
+			for i := 0; i < rv.Len(); i++ {
+				ret = append(ret, FormatValues(rv.Index(i).Interface())...)
+			}
+			return ret
+		}
+	}
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil
+	}
+	return []string{formatValue(v)}
+}
+
+func formatValue(v interface{}) string {
+	switch x := v.(type) {
+	case encoding.TextMarshaler:
+		b, err := x.MarshalText()
+		if err == nil {
+			return string(b)
+		}
+	case []byte:
+		return string(x)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		return formatValue(rv.Elem().Interface())
+	}
+	return fmt.Sprint(v)
+}
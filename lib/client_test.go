@@ -0,0 +1,71 @@
+package httper
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientError(t *testing.T) {
+	tests := []struct {
+		name        string
+		handler     http.HandlerFunc
+		wantStatus  int
+		wantProblem bool
+		wantMessage string
+	}{
+		{"problem", func(w http.ResponseWriter, r *http.Request) {
+			NewProblem(NotFound("item_not_found", "no such item", nil), r).Write(w)
+		}, http.StatusNotFound, true, "404 Not Found: no such item"},
+		{"plain body", func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "boom", http.StatusInternalServerError)
+		}, http.StatusInternalServerError, false, "500 Internal Server Error: boom"},
+		{"malformed problem", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("{"))
+		}, http.StatusBadRequest, false, "400 Bad Request: {"},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(tc.handler)
+			defer srv.Close()
+			_, err := NewClient(srv.URL, nil).Do(NewClientRequest("GET", "/items"))
+			var clientErr *ClientError
+			if !errors.As(err, &clientErr) {
+				t.Fatalf("want a *ClientError, got %T %v", err, err)
+			}
+			if clientErr.StatusCode != tc.wantStatus {
+				t.Fatalf("want the status %v, got %v", tc.wantStatus, clientErr.StatusCode)
+			}
+			if (clientErr.Problem != nil) != tc.wantProblem {
+				t.Fatalf("want a decoded problem %v, got %+v", tc.wantProblem, clientErr.Problem)
+			}
+			if tc.wantProblem && clientErr.Problem.Code != "item_not_found" {
+				t.Fatalf("want the code item_not_found, got %q", clientErr.Problem.Code)
+			}
+			if msg := strings.TrimSpace(clientErr.Error()); msg != tc.wantMessage {
+				t.Fatalf("want the message %q, got %q", tc.wantMessage, msg)
+			}
+		})
+	}
+}
+
+func TestClientSuccess(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":3}`))
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL, nil)
+	res, err := c.Do(NewClientRequest("GET", "/items"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := struct{ ID int }{}
+	if err := c.Decode(res, &out); err != nil || out.ID != 3 {
+		t.Fatalf("want the id 3, got %v %v", out.ID, err)
+	}
+}
@@ -0,0 +1,55 @@
+package httper
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+type contextKey string
+
+// requestIDKey is the context key of the request id.
+var requestIDKey = contextKey("request-id")
+
+// WithContextValue returns a shallow copy of r with key/value added to its context,
+// middlewares use it to pass values to the methods receiving a context.Context.
+func WithContextValue(r *http.Request, key, value interface{}) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), key, value))
+}
+
+// WithRequestID returns a shallow copy of r with id as its request id.
+func WithRequestID(r *http.Request, id string) *http.Request {
+	return WithContextValue(r, requestIDKey, id)
+}
+
+// RequestIDFromContext returns the request id of ctx, or an empty string.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// RequestID returns the middleware of RequestIDHandler.
+func RequestID(header string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return RequestIDHandler(header, next)
+	}
+}
+
+// RequestIDHandler installs a request id into the context of the requests,
+// it is read from the header, or generated when it is empty.
+func RequestIDHandler(header string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(header)
+		if id == "" {
+			b := make([]byte, 16)
+			if _, err := rand.Read(b); err != nil {
+				NewProblem(err, r).Write(w)
+				return
+			}
+			id = hex.EncodeToString(b)
+		}
+		w.Header().Set(header, id)
+		next.ServeHTTP(w, WithRequestID(r, id))
+	})
+}
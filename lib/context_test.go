@@ -0,0 +1,62 @@
+package httper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestIDHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    string
+		wantID    string
+		generated bool
+	}{
+		{"from the header", "abc", "abc", false},
+		{"generated", "", "", true},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := ""
+			h := RequestID("X-Request-Id")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = RequestIDFromContext(r.Context())
+			}))
+			req := httptest.NewRequest("GET", "/", nil)
+			if tc.header != "" {
+				req.Header.Set("X-Request-Id", tc.header)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if tc.generated {
+				if len(got) != 32 {
+					t.Fatalf("want a generated id of 32 hex chars, got %q", got)
+				}
+			} else if got != tc.wantID {
+				t.Fatalf("want the id %q, got %q", tc.wantID, got)
+			}
+			if h := w.Header().Get("X-Request-Id"); h != got {
+				t.Fatalf("want the response header %q, got %q", got, h)
+			}
+		})
+	}
+}
+
+func TestRequestIDFromContext(t *testing.T) {
+	if id := RequestIDFromContext(context.Background()); id != "" {
+		t.Fatalf("want no id, got %q", id)
+	}
+	r := WithRequestID(httptest.NewRequest("GET", "/", nil), "abc")
+	if id := RequestIDFromContext(r.Context()); id != "abc" {
+		t.Fatalf("want the id abc, got %q", id)
+	}
+	r = WithContextValue(r, contextKey("user"), "bob")
+	if v := r.Context().Value(contextKey("user")); v != "bob" {
+		t.Fatalf("want the value bob, got %v", v)
+	}
+	if id := RequestIDFromContext(r.Context()); id != "abc" {
+		t.Fatalf("want the id abc to be kept, got %q", id)
+	}
+}
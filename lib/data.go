@@ -1,6 +1,7 @@
 package httper
 
 import (
+	"mime/multipart"
 	"net/http"
 	"net/url"
 
@@ -11,6 +12,7 @@ import (
 // Dataer defines a data provider requirements.
 type Dataer interface {
 	Get(prefix, name string) string
+	GetAll(prefix, name string) []string
 	GetAny(prefix, name string) interface{}
 }
 
@@ -41,7 +43,22 @@ func NewDataProviderFacade(providers ...DataProvider) *DataProviderFacade {
 
 // Get a string
 func (c *DataProviderFacade) Get(prefix, name string) string {
-	return c.GetAny(prefix, name).(string)
+	s, _ := c.GetAny(prefix, name).(string)
+	return s
+}
+
+// GetAll strings
+func (c *DataProviderFacade) GetAll(prefix, name string) []string {
+	var ret []string
+	for _, p := range c.Providers {
+		if p.IsAbout(prefix) {
+			ret = p.GetAll(prefix, name)
+			if ret != nil {
+				break
+			}
+		}
+	}
+	return ret
 }
 
 // GetAny kind of value
@@ -49,7 +66,7 @@ func (c *DataProviderFacade) GetAny(prefix, name string) interface{} {
 	var ret interface{}
 	for _, p := range c.Providers {
 		if p.IsAbout(prefix) {
-			ret = p.Get(prefix, name)
+			ret = p.GetAny(prefix, name)
 			if ret != nil {
 				break
 			}
@@ -59,17 +76,34 @@ func (c *DataProviderFacade) GetAny(prefix, name string) interface{} {
 }
 
 // StdHTTPDataProvider returns a data provider for a standard http handlingr.
-type StdHTTPDataProvider struct{}
+type StdHTTPDataProvider struct {
+	store SessionStore
+	// MaxMemory to parse the multipart forms of the file prefixed data,
+	// DefaultMaxMemory when it is 0.
+	MaxMemory int64
+}
+
+// NewStdHTTPDataProvider constructs a StdHTTPDataProvider
+// reading the sessions of store.
+func NewStdHTTPDataProvider(store SessionStore) *StdHTTPDataProvider {
+	return &StdHTTPDataProvider{store: store}
+}
 
 // Make returns a DataHelper
 func (c StdHTTPDataProvider) Make(w http.ResponseWriter, r *http.Request) Dataer {
 	query := r.URL.Query()
-	return NewDataProviderFacade(
+	ret := NewDataProviderFacade(
 		&GetHTTPDataProvider{w, r, query},
 		&PostHTTPDataProvider{w, r},
 		&CookieHTTPDataProvider{w, r},
 		&ReqHTTPDataProvider{w, r, query},
+		&FileHTTPDataProvider{w, r, c.MaxMemory},
+		&HeaderHTTPDataProvider{w, r},
 	)
+	if c.store != nil {
+		ret.Providers = append(ret.Providers, &SessionHTTPDataProvider{w, r, c.store.Load(r)})
+	}
+	return ret
 }
 
 // MakeEmpty returns a DataHelper
@@ -79,9 +113,17 @@ func (c StdHTTPDataProvider) MakeEmpty() Dataer {
 		&PostHTTPDataProvider{},
 		&CookieHTTPDataProvider{},
 		&ReqHTTPDataProvider{},
+		&FileHTTPDataProvider{},
+		&HeaderHTTPDataProvider{},
+		&SessionHTTPDataProvider{},
 	)
 }
 
+// SetMaxMemory to parse the multipart forms.
+func (c *StdHTTPDataProvider) SetMaxMemory(n int64) {
+	c.MaxMemory = n
+}
+
 // GorillaHTTPDataProvider returns a data provider for a standard http handlingr.
 type GorillaHTTPDataProvider struct {
 	StdHTTPDataProvider
@@ -89,6 +131,12 @@ type GorillaHTTPDataProvider struct {
 	Name  string
 }
 
+// NewGorillaHTTPDataProvider constructs a GorillaHTTPDataProvider
+// reading the session name within store.
+func NewGorillaHTTPDataProvider(store sessions.Store, name string) *GorillaHTTPDataProvider {
+	return &GorillaHTTPDataProvider{Name: name, store: store}
+}
+
 // Make returns a DataHelper
 func (c GorillaHTTPDataProvider) Make(w http.ResponseWriter, r *http.Request) Dataer {
 	ret := c.StdHTTPDataProvider.Make(w, r).(*DataProviderFacade)
@@ -149,6 +197,14 @@ func (c GetHTTPDataProvider) Get(prefix, name string) string {
 	return ""
 }
 
+// GetAll strings
+func (c GetHTTPDataProvider) GetAll(prefix, name string) []string {
+	if val, ok := c.query[name]; ok {
+		return val
+	}
+	return nil
+}
+
 // GetAny kind of value
 func (c GetHTTPDataProvider) GetAny(prefix, name string) interface{} {
 	return c.Get(prefix, name)
@@ -178,6 +234,17 @@ func (c CookieHTTPDataProvider) Get(prefix, name string) string {
 	return ""
 }
 
+// GetAll strings
+func (c CookieHTTPDataProvider) GetAll(prefix, name string) []string {
+	var ret []string
+	for _, cookie := range c.r.Cookies() {
+		if cookie.Name == name {
+			ret = append(ret, cookie.Value)
+		}
+	}
+	return ret
+}
+
 // GetAny kind of value
 func (c CookieHTTPDataProvider) GetAny(prefix, name string) interface{} {
 	return c.Get(prefix, name)
@@ -213,6 +280,19 @@ func (c ReqHTTPDataProvider) Get(prefix, name string) string {
 	return ""
 }
 
+// GetAll strings
+func (c ReqHTTPDataProvider) GetAll(prefix, name string) []string {
+	if val, ok := c.query[name]; ok {
+		return val
+	}
+	if err := c.r.ParseForm(); err == nil {
+		if val, ok := c.r.Form[name]; ok {
+			return val
+		}
+	}
+	return nil
+}
+
 // GetAny kind of value
 func (c ReqHTTPDataProvider) GetAny(prefix, name string) interface{} {
 	return c.Get(prefix, name)
@@ -244,11 +324,115 @@ func (c PostHTTPDataProvider) Get(prefix, name string) string {
 	return ""
 }
 
+// GetAll strings
+func (c PostHTTPDataProvider) GetAll(prefix, name string) []string {
+	if err := c.r.ParseForm(); err == nil {
+		if val, ok := c.r.Form[name]; ok {
+			return val
+		}
+	}
+	return nil
+}
+
 // GetAny kind of value
 func (c PostHTTPDataProvider) GetAny(prefix, name string) interface{} {
 	return c.Get(prefix, name)
 }
 
+// FileHTTPDataProvider helps to deal with the files of multipart forms.
+type FileHTTPDataProvider struct {
+	w         http.ResponseWriter
+	r         *http.Request
+	maxMemory int64
+}
+
+// IsAbout returns true when prefix is file
+func (c FileHTTPDataProvider) IsAbout(prefix string) bool {
+	return prefix == c.GetName()
+}
+
+// GetName returns file
+func (c FileHTTPDataProvider) GetName() string {
+	return "file"
+}
+
+// Get the name of the first file.
+func (c FileHTTPDataProvider) Get(prefix, name string) string {
+	if files := c.Files(name); len(files) > 0 {
+		return files[0].Filename
+	}
+	return ""
+}
+
+// GetAll the names of the files.
+func (c FileHTTPDataProvider) GetAll(prefix, name string) []string {
+	var ret []string
+	for _, f := range c.Files(name) {
+		ret = append(ret, f.Filename)
+	}
+	return ret
+}
+
+// GetAny returns the []*multipart.FileHeader of name.
+func (c FileHTTPDataProvider) GetAny(prefix, name string) interface{} {
+	if files := c.Files(name); files != nil {
+		return files
+	}
+	return nil
+}
+
+// Files returns the files of name,
+// the multipart form is parsed when needed.
+func (c FileHTTPDataProvider) Files(name string) []*multipart.FileHeader {
+	if c.r.MultipartForm == nil {
+		maxMemory := c.maxMemory
+		if maxMemory == 0 {
+			maxMemory = DefaultMaxMemory
+		}
+		if err := c.r.ParseMultipartForm(maxMemory); err != nil {
+			return nil
+		}
+	}
+	if c.r.MultipartForm == nil {
+		return nil
+	}
+	return c.r.MultipartForm.File[name]
+}
+
+// HeaderHTTPDataProvider helps to deal with the request headers.
+type HeaderHTTPDataProvider struct {
+	w http.ResponseWriter
+	r *http.Request
+}
+
+// IsAbout returns true when prefix is header
+func (c HeaderHTTPDataProvider) IsAbout(prefix string) bool {
+	return prefix == c.GetName()
+}
+
+// GetName returns header
+func (c HeaderHTTPDataProvider) GetName() string {
+	return "header"
+}
+
+// Get a string
+func (c HeaderHTTPDataProvider) Get(prefix, name string) string {
+	return c.r.Header.Get(name)
+}
+
+// GetAll strings
+func (c HeaderHTTPDataProvider) GetAll(prefix, name string) []string {
+	if val, ok := c.r.Header[http.CanonicalHeaderKey(name)]; ok {
+		return val
+	}
+	return nil
+}
+
+// GetAny kind of value
+func (c HeaderHTTPDataProvider) GetAny(prefix, name string) interface{} {
+	return c.Get(prefix, name)
+}
+
 // URLHTTPDataProvider helps to deal with URL.
 type URLHTTPDataProvider struct {
 	w     http.ResponseWriter
@@ -278,6 +462,17 @@ func (c URLHTTPDataProvider) Get(prefix, name string) string {
 	return ""
 }
 
+// GetAll strings
+func (c URLHTTPDataProvider) GetAll(prefix, name string) []string {
+	if val, ok := c.query[name]; ok {
+		return val
+	}
+	if val, ok := c.vars[name]; ok {
+		return []string{val}
+	}
+	return nil
+}
+
 // GetAny kind of value
 func (c URLHTTPDataProvider) GetAny(prefix, name string) interface{} {
 	return c.Get(prefix, name)
@@ -308,6 +503,14 @@ func (c RouteHTTPDataProvider) Get(prefix, name string) string {
 	return ""
 }
 
+// GetAll strings
+func (c RouteHTTPDataProvider) GetAll(prefix, name string) []string {
+	if val, ok := c.vars[name]; ok {
+		return []string{val}
+	}
+	return nil
+}
+
 // GetAny kind of value
 func (c RouteHTTPDataProvider) GetAny(prefix, name string) interface{} {
 	return c.Get(prefix, name)
@@ -332,7 +535,19 @@ func (c GorillaSessionHTTPDataProvider) GetName() string {
 
 // Get a string
 func (c GorillaSessionHTTPDataProvider) Get(prefix, name string) string {
-	return c.GetAny(prefix, name).(string)
+	s, _ := c.GetAny(prefix, name).(string)
+	return s
+}
+
+// GetAll strings
+func (c GorillaSessionHTTPDataProvider) GetAll(prefix, name string) []string {
+	switch val := c.GetAny(prefix, name).(type) {
+	case []string:
+		return val
+	case string:
+		return []string{val}
+	}
+	return nil
 }
 
 // GetAny kind of value
@@ -342,3 +557,53 @@ func (c GorillaSessionHTTPDataProvider) GetAny(prefix, name string) interface{}
 	}
 	return nil
 }
+
+// SessionHTTPDataProvider helps to deal with the sessions of a SessionStore.
+type SessionHTTPDataProvider struct {
+	w      http.ResponseWriter
+	r      *http.Request
+	values map[string]interface{}
+}
+
+// IsAbout returns true when prefix is session
+func (c SessionHTTPDataProvider) IsAbout(prefix string) bool {
+	return prefix == c.GetName()
+}
+
+// GetName returns session
+func (c SessionHTTPDataProvider) GetName() string {
+	return "session"
+}
+
+// Get a string
+func (c SessionHTTPDataProvider) Get(prefix, name string) string {
+	s, _ := c.GetAny(prefix, name).(string)
+	return s
+}
+
+// GetAll strings
+func (c SessionHTTPDataProvider) GetAll(prefix, name string) []string {
+	switch val := c.GetAny(prefix, name).(type) {
+	case []string:
+		return val
+	case []interface{}:
+		ret := []string{}
+		for _, v := range val {
+			if s, ok := v.(string); ok {
+				ret = append(ret, s)
+			}
+		}
+		return ret
+	case string:
+		return []string{val}
+	}
+	return nil
+}
+
+// GetAny kind of value
+func (c SessionHTTPDataProvider) GetAny(prefix, name string) interface{} {
+	if val, ok := c.values[name]; ok {
+		return val
+	}
+	return nil
+}
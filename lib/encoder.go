@@ -0,0 +1,77 @@
+package httper
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// Encoder encodes the results of a method into an http response body.
+type Encoder interface {
+	Encode(w io.Writer, values ...interface{}) error
+	ContentType() string
+}
+
+// JSONEncoder encodes the results as json,
+// a single value is encoded as is, multiple values as an array.
+type JSONEncoder struct{}
+
+// Encode values to w.
+func (e JSONEncoder) Encode(w io.Writer, values ...interface{}) error {
+	if len(values) == 0 {
+		return nil
+	}
+	if len(values) == 1 {
+		return json.NewEncoder(w).Encode(values[0])
+	}
+	return json.NewEncoder(w).Encode(values)
+}
+
+// ContentType returns application/json
+func (e JSONEncoder) ContentType() string {
+	return "application/json"
+}
+
+// XMLEncoder encodes the results as xml.
+type XMLEncoder struct{}
+
+// Encode values to w.
+func (e XMLEncoder) Encode(w io.Writer, values ...interface{}) error {
+	enc := xml.NewEncoder(w)
+	for _, v := range values {
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	}
+	return enc.Flush()
+}
+
+// ContentType returns application/xml
+func (e XMLEncoder) ContentType() string {
+	return "application/xml"
+}
+
+// TextEncoder prints the results, one per line.
+type TextEncoder struct{}
+
+// Encode values to w.
+func (e TextEncoder) Encode(w io.Writer, values ...interface{}) error {
+	for _, v := range values {
+		var err error
+		if b, ok := v.([]byte); ok {
+			_, err = fmt.Fprintf(w, "%s\n", b)
+		} else {
+			_, err = fmt.Fprintln(w, v)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ContentType returns text/plain
+func (e TextEncoder) ContentType() string {
+	return "text/plain"
+}
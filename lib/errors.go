@@ -0,0 +1,127 @@
+package httper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// HTTPError is an error able to tell about its http response.
+type HTTPError interface {
+	error
+	// StatusCode of the response.
+	StatusCode() int
+	// PublicMessage is safe to show to the client.
+	PublicMessage() string
+	// Code is a machine readable code of the error.
+	Code() string
+}
+
+// Error implements HTTPError.
+type Error struct {
+	Status  int
+	Message string
+	ErrCode string
+	// Err is the internal cause, it is not shown to the client.
+	Err error
+}
+
+// NewError constructs an Error.
+func NewError(status int, code, message string, err error) *Error {
+	return &Error{Status: status, ErrCode: code, Message: message, Err: err}
+}
+
+// BadRequest constructs an http 400 Error.
+func BadRequest(code, message string, err error) *Error {
+	return NewError(http.StatusBadRequest, code, message, err)
+}
+
+// NotFound constructs an http 404 Error.
+func NotFound(code, message string, err error) *Error {
+	return NewError(http.StatusNotFound, code, message, err)
+}
+
+// ParamError constructs the http 400 Error of a parameter which could not be converted.
+func ParamError(prefix, name string, err error) *Error {
+	return BadRequest("invalid_parameter", fmt.Sprintf("invalid %v parameter %q", prefix, name), err)
+}
+
+// BodyError constructs the http 400 Error of a request body which could not be decoded,
+// it is an http 413 Error when the body exceeds the limit of an http.MaxBytesReader.
+func BodyError(err error) *Error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return NewError(http.StatusRequestEntityTooLarge, "body_too_large",
+			fmt.Sprintf("request body larger than %v bytes", tooLarge.Limit), err)
+	}
+	return BadRequest("invalid_body", "invalid request body", err)
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%v: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+// StatusCode of the response.
+func (e *Error) StatusCode() int {
+	return e.Status
+}
+
+// PublicMessage is safe to show to the client.
+func (e *Error) PublicMessage() string {
+	return e.Message
+}
+
+// Code is a machine readable code of the error.
+func (e *Error) Code() string {
+	return e.ErrCode
+}
+
+// Unwrap returns the internal cause.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Problem is an RFC 7807 problem details.
+type Problem struct {
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code,omitempty"`
+	// Errors are the violations of a ValidationError.
+	Errors Violations `json:"errors,omitempty"`
+}
+
+// NewProblem returns the problem of err,
+// errors which are not an HTTPError are http 500 without details.
+func NewProblem(err error, r *http.Request) *Problem {
+	p := &Problem{Status: http.StatusInternalServerError}
+	var x HTTPError
+	if errors.As(err, &x) {
+		p.Status = x.StatusCode()
+		p.Detail = x.PublicMessage()
+		p.Code = x.Code()
+	}
+	var v *ValidationError
+	if errors.As(err, &v) {
+		p.Errors = v.Violations
+	}
+	p.Title = http.StatusText(p.Status)
+	if r != nil && r.URL != nil {
+		p.Instance = r.URL.Path
+	}
+	return p
+}
+
+// Write the problem as application/problem+json.
+func (p *Problem) Write(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	return json.NewEncoder(w).Encode(p)
+}
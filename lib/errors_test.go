@@ -0,0 +1,90 @@
+package httper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewProblem(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantDetail string
+	}{
+		{"http error", NotFound("item_not_found", "no such item", nil), http.StatusNotFound, "item_not_found", "no such item"},
+		{"wrapped http error", fmt.Errorf("get: %w", BadRequest("bad", "bad input", nil)), http.StatusBadRequest, "bad", "bad input"},
+		{"internal cause", NewError(http.StatusConflict, "conflict", "conflict", errors.New("secret")), http.StatusConflict, "conflict", "conflict"},
+		{"param error", ParamError("get", "id", errors.New("x")), http.StatusBadRequest, "invalid_parameter", `invalid get parameter "id"`},
+		{"plain error", errors.New("secret"), http.StatusInternalServerError, "", ""},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewProblem(tc.err, httptest.NewRequest("GET", "/items/1?x=y", nil))
+			if p.Status != tc.wantStatus || p.Code != tc.wantCode || p.Detail != tc.wantDetail {
+				t.Fatalf("want the problem %v %q %q, got %+v", tc.wantStatus, tc.wantCode, tc.wantDetail, p)
+			}
+			if p.Title != http.StatusText(tc.wantStatus) {
+				t.Fatalf("want the title %q, got %q", http.StatusText(tc.wantStatus), p.Title)
+			}
+			if p.Instance != "/items/1" {
+				t.Fatalf("want the instance /items/1, got %q", p.Instance)
+			}
+		})
+	}
+	if p := NewProblem(errors.New("x"), nil); p.Instance != "" {
+		t.Fatalf("want no instance without a request, got %q", p.Instance)
+	}
+}
+
+func TestProblemWrite(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := NewProblem(NotFound("item_not_found", "no such item", nil), nil).Write(w); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("want the status %v, got %v", http.StatusNotFound, w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Fatalf("want the content type application/problem+json, got %q", ct)
+	}
+	got := &Problem{}
+	if err := json.Unmarshal(w.Body.Bytes(), got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != http.StatusNotFound || got.Code != "item_not_found" || got.Title != "Not Found" {
+		t.Fatalf("unexpected problem %+v", got)
+	}
+}
+
+func TestBodyError(t *testing.T) {
+	tooLarge := &http.MaxBytesError{Limit: 10}
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{"invalid", errors.New("unexpected EOF"), http.StatusBadRequest, "invalid_body"},
+		{"too large", tooLarge, http.StatusRequestEntityTooLarge, "body_too_large"},
+		{"wrapped too large", fmt.Errorf("decode: %w", tooLarge), http.StatusRequestEntityTooLarge, "body_too_large"},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := BodyError(tc.err)
+			if err.StatusCode() != tc.wantStatus || err.Code() != tc.wantCode {
+				t.Fatalf("want the error %v %v, got %v %v", tc.wantStatus, tc.wantCode, err.StatusCode(), err.Code())
+			}
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("want the cause %v, got %v", tc.err, err.Err)
+			}
+		})
+	}
+}
@@ -1,30 +1,41 @@
 package httper
 
 import (
+	"bufio"
+	"bytes"
+	"errors"
 	"io"
+	"mime"
 	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 // Finalizer finalizes an htpp response.
 type Finalizer interface {
 	HandleError(err error, w io.Writer, r *http.Request) bool
-	HandleSuccess(w io.Writer, r io.Reader) error
+	// HandleSuccess writes the results of a method,
+	// a single io.Reader value is copied as is.
+	HandleSuccess(w io.Writer, r *http.Request, values ...interface{}) error
 }
 
 // DefaultFinalizer for an http response.
 type DefaultFinalizer struct {
 }
 
-// HandleError prints http 500.
+// HandleError prints an RFC 7807 problem,
+// its status is taken from HTTPError, it defaults to http 500.
 func (f DefaultFinalizer) HandleError(err error, w io.Writer, r *http.Request) bool {
 	if x, ok := w.(http.ResponseWriter); ok {
-		http.Error(x, err.Error(), http.StatusInternalServerError)
+		NewProblem(err, r).Write(x)
 	}
 	return true
 }
 
-// HandleSuccess prints http 200 and prints r.
-func (f DefaultFinalizer) HandleSuccess(w io.Writer, r io.Reader) error {
+// HandleSuccess prints http 200.
+func (f DefaultFinalizer) HandleSuccess(w io.Writer, r *http.Request, values ...interface{}) error {
 	if x, ok := w.(http.ResponseWriter); ok {
 		x.WriteHeader(http.StatusOK)
 	}
@@ -34,11 +45,207 @@ func (f DefaultFinalizer) HandleSuccess(w io.Writer, r io.Reader) error {
 // HTTPFinalizer finalizes an HTTP response.
 type HTTPFinalizer struct {
 	DefaultFinalizer
+	// Encoder of the values, defaults to JSONEncoder.
+	Encoder Encoder
 }
 
-// HandleSuccess prints http 200 and prints r.
-func (f HTTPFinalizer) HandleSuccess(w io.Writer, r io.Reader) error {
+// HandleSuccess prints http 200 and prints the values.
+func (f HTTPFinalizer) HandleSuccess(w io.Writer, r *http.Request, values ...interface{}) error {
+	encoder := f.Encoder
+	if encoder == nil {
+		encoder = &JSONEncoder{}
+	}
+	return f.writeValues(w, r, []Encoder{encoder}, values...)
+}
+
+// writeValues prints the values with the first of the encoders succeeding to encode them,
+// it prints http 406 when none of them succeeds.
+func (f HTTPFinalizer) writeValues(w io.Writer, r *http.Request, encoders []Encoder, values ...interface{}) error {
+	if len(values) == 0 {
+		return f.DefaultFinalizer.HandleSuccess(w, r)
+	}
+	if len(values) == 1 {
+		if reader, ok := values[0].(io.Reader); ok {
+			return f.copyReader(w, r, reader)
+		}
+	}
+	var b bytes.Buffer
+	var err error
+	for _, encoder := range encoders {
+		b.Reset()
+		if err = encoder.Encode(&b, values...); err != nil {
+			continue
+		}
+		if x, ok := w.(http.ResponseWriter); ok {
+			x.Header().Set("Content-Type", encoder.ContentType())
+		}
+		f.DefaultFinalizer.HandleSuccess(w, r)
+		_, err = io.Copy(w, &b)
+		return err
+	}
+	if err == nil {
+		err = errors.New("no acceptable encoder")
+	}
+	f.HandleError(NewError(http.StatusNotAcceptable, "not_acceptable",
+		"the results can not be encoded in an acceptable content type", err), w, r)
+	return err
+}
+
+// ContentTyper is an io.Reader result which knows its content type.
+type ContentTyper interface {
+	ContentType() string
+}
+
+// copyReader prints the content of reader, its Content-Type
+// is provided by a ContentTyper, or detected from its first bytes.
+func (f HTTPFinalizer) copyReader(w io.Writer, r *http.Request, reader io.Reader) error {
+	if rv := reflect.ValueOf(reader); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		// a nil reader, such as a zero value, has an empty content.
+		f.DefaultFinalizer.HandleSuccess(w, r)
+		return nil
+	}
+	if x, ok := w.(http.ResponseWriter); ok && x.Header().Get("Content-Type") == "" {
+		if c, ok := reader.(ContentTyper); ok {
+			x.Header().Set("Content-Type", c.ContentType())
+		} else {
+			buf := bufio.NewReaderSize(reader, 512)
+			head, _ := buf.Peek(512)
+			x.Header().Set("Content-Type", http.DetectContentType(head))
+			reader = buf
+		}
+	}
 	f.DefaultFinalizer.HandleSuccess(w, r)
-	_, err := io.Copy(w, r)
+	_, err := io.Copy(w, reader)
 	return err
 }
+
+// NegotiatingFinalizer finalizes an HTTP response
+// with the Encoder matching the Accept header of the request.
+type NegotiatingFinalizer struct {
+	HTTPFinalizer
+	// Encoders by order of preference.
+	Encoders []Encoder
+}
+
+// NewNegotiatingFinalizer constructs a NegotiatingFinalizer of encoders,
+// it defaults to JSONEncoder, XMLEncoder, TextEncoder.
+func NewNegotiatingFinalizer(encoders ...Encoder) *NegotiatingFinalizer {
+	if len(encoders) == 0 {
+		encoders = []Encoder{&JSONEncoder{}, &XMLEncoder{}, &TextEncoder{}}
+	}
+	return &NegotiatingFinalizer{Encoders: encoders}
+}
+
+// RegisterEncoder adds an encoder, it replaces the one handling the same content type.
+func (f *NegotiatingFinalizer) RegisterEncoder(e Encoder) {
+	for i, x := range f.Encoders {
+		if x.ContentType() == e.ContentType() {
+			f.Encoders[i] = e
+			return
+		}
+	}
+	f.Encoders = append(f.Encoders, e)
+}
+
+// HandleSuccess prints http 200 and prints the values with the most acceptable encoder
+// succeeding to encode them, it prints http 406 when none is acceptable.
+func (f *NegotiatingFinalizer) HandleSuccess(w io.Writer, r *http.Request, values ...interface{}) error {
+	return f.writeValues(w, r, f.Acceptable(r), values...)
+}
+
+// Negotiate returns the most acceptable encoder for r, or nil.
+func (f *NegotiatingFinalizer) Negotiate(r *http.Request) Encoder {
+	if encoders := f.Acceptable(r); len(encoders) > 0 {
+		return encoders[0]
+	}
+	return nil
+}
+
+// Acceptable returns the encoders acceptable for the Accept header of r,
+// by order of preference. An encoder takes the quality of the most specific
+// media range matching it (RFC 7231 5.3.2), it is excluded by a quality of 0.
+// Equal qualities are ordered by specificity, then by order of f.Encoders.
+func (f *NegotiatingFinalizer) Acceptable(r *http.Request) []Encoder {
+	accept := ""
+	if r != nil {
+		accept = r.Header.Get("Accept")
+	}
+	if accept == "" {
+		return f.Encoders
+	}
+	ranges := parseAccept(accept)
+	type candidate struct {
+		encoder     Encoder
+		q           float64
+		specificity int
+	}
+	candidates := []candidate{}
+	for _, e := range f.Encoders {
+		match := acceptedType{Specificity: -1}
+		for _, mediaRange := range ranges {
+			if mediaRange.Match(e.ContentType()) && mediaRange.Specificity > match.Specificity {
+				match = mediaRange
+			}
+		}
+		if match.Specificity >= 0 && match.Q > 0 {
+			candidates = append(candidates, candidate{e, match.Q, match.Specificity})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].q != candidates[j].q {
+			return candidates[i].q > candidates[j].q
+		}
+		return candidates[i].specificity > candidates[j].specificity
+	})
+	ret := []Encoder{}
+	for _, c := range candidates {
+		ret = append(ret, c.encoder)
+	}
+	return ret
+}
+
+// acceptedType is a media range of an Accept header.
+type acceptedType struct {
+	Type string
+	Q    float64
+	// Specificity is 0 for */*, 1 for type/*, 2 for type/subtype.
+	Specificity int
+}
+
+// Match a content type such as application/json.
+func (a acceptedType) Match(contentType string) bool {
+	contentType, _, _ = mime.ParseMediaType(contentType)
+	if a.Type == "*/*" || a.Type == contentType {
+		return true
+	}
+	if strings.HasSuffix(a.Type, "/*") {
+		return strings.HasPrefix(contentType, a.Type[:len(a.Type)-1])
+	}
+	return false
+}
+
+// parseAccept parses the media ranges of an Accept header,
+// the ranges having a quality of 0 are kept to exclude their types.
+func parseAccept(accept string) []acceptedType {
+	ret := []acceptedType{}
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if x, err := strconv.ParseFloat(v, 64); err == nil {
+				q = x
+			}
+		}
+		specificity := 2
+		if mediaType == "*/*" {
+			specificity = 0
+		} else if strings.HasSuffix(mediaType, "/*") {
+			specificity = 1
+		}
+		ret = append(ret, acceptedType{Type: mediaType, Q: q, Specificity: specificity})
+	}
+	return ret
+}
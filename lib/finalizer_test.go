@@ -0,0 +1,122 @@
+package httper
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNegotiatingFinalizerAcceptable(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   []string
+	}{
+		{"", []string{"application/json", "application/xml", "text/plain"}},
+		{"application/xml", []string{"application/xml"}},
+		{"text/html", []string{}},
+		{"*/*", []string{"application/json", "application/xml", "text/plain"}},
+		{"*/*;q=0.1, application/xml", []string{"application/xml", "application/json", "text/plain"}},
+		{"*/*, application/xml", []string{"application/xml", "application/json", "text/plain"}},
+		{"application/*, application/xml", []string{"application/xml", "application/json"}},
+		{"application/json;q=0, */*", []string{"application/xml", "text/plain"}},
+		{"application/*;q=0, text/plain;q=0.5, */*", []string{"text/plain"}},
+		{"text/plain;q=0.5, application/json;q=0.8", []string{"application/json", "text/plain"}},
+		{"text/*;q=0.9, application/json; charset=utf-8", []string{"application/json", "text/plain"}},
+	}
+	f := NewNegotiatingFinalizer()
+	for _, tc := range tests {
+		t.Run(tc.accept, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			if tc.accept != "" {
+				r.Header.Set("Accept", tc.accept)
+			}
+			got := []string{}
+			for _, e := range f.Acceptable(r) {
+				got = append(got, e.ContentType())
+			}
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Fatalf("want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+type xmlValue struct {
+	A int
+}
+
+func TestNegotiatingFinalizerHandleSuccess(t *testing.T) {
+	tests := []struct {
+		name            string
+		accept          string
+		values          []interface{}
+		wantCode        int
+		wantContentType string
+		wantBody        string
+	}{
+		{"default", "", []interface{}{map[string]int{"a": 1}}, http.StatusOK, "application/json", `{"a":1}`},
+		{"xml", "application/xml", []interface{}{xmlValue{A: 1}}, http.StatusOK, "application/xml", "<A>1</A>"},
+		{"xml fallback", "application/xml, */*;q=0.5", []interface{}{map[string]int{"a": 1}}, http.StatusOK, "application/json", `{"a":1}`},
+		{"xml only", "application/xml", []interface{}{map[string]int{"a": 1}}, http.StatusNotAcceptable, "application/problem+json", "not_acceptable"},
+		{"not acceptable", "image/png", []interface{}{1}, http.StatusNotAcceptable, "application/problem+json", "not_acceptable"},
+		{"no values", "image/png", nil, http.StatusOK, "", ""},
+		{"text", "text/plain", []interface{}{"hello"}, http.StatusOK, "text/plain", "hello"},
+	}
+	f := NewNegotiatingFinalizer()
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			if tc.accept != "" {
+				r.Header.Set("Accept", tc.accept)
+			}
+			w := httptest.NewRecorder()
+			f.HandleSuccess(w, r, tc.values...)
+			if w.Code != tc.wantCode {
+				t.Fatalf("want code %v, got %v %v", tc.wantCode, w.Code, w.Body.String())
+			}
+			if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, tc.wantContentType) {
+				t.Fatalf("want content type %q, got %q", tc.wantContentType, got)
+			}
+			if !strings.Contains(w.Body.String(), tc.wantBody) {
+				t.Fatalf("want body containing %q, got %q", tc.wantBody, w.Body.String())
+			}
+		})
+	}
+}
+
+type typedReader struct {
+	*strings.Reader
+}
+
+func (typedReader) ContentType() string { return "text/csv" }
+
+func TestHTTPFinalizerReader(t *testing.T) {
+	var nilBuffer *bytes.Buffer
+	tests := []struct {
+		name            string
+		value           interface{}
+		wantContentType string
+		wantBody        string
+	}{
+		{"detected", strings.NewReader("<html><body>x</body></html>"), "text/html", "<html><body>x</body></html>"},
+		{"content typer", typedReader{strings.NewReader("a,b")}, "text/csv", "a,b"},
+		{"nil", nilBuffer, "", ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			HTTPFinalizer{}.HandleSuccess(w, httptest.NewRequest("GET", "/", nil), tc.value)
+			if w.Code != http.StatusOK {
+				t.Fatalf("want code 200, got %v", w.Code)
+			}
+			if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, tc.wantContentType) {
+				t.Fatalf("want content type %q, got %q", tc.wantContentType, got)
+			}
+			if w.Body.String() != tc.wantBody {
+				t.Fatalf("want body %q, got %q", tc.wantBody, w.Body.String())
+			}
+		})
+	}
+}
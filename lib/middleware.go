@@ -0,0 +1,34 @@
+package httper
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Middleware wraps an http.Handler.
+type Middleware func(http.Handler) http.Handler
+
+// Chain wraps h with m, the first middleware is the outermost.
+func Chain(h http.Handler, m ...Middleware) http.Handler {
+	for i := len(m) - 1; i >= 0; i-- {
+		if m[i] != nil {
+			h = m[i](h)
+		}
+	}
+	return h
+}
+
+// ResolveMiddlewares returns the global middlewares followed by the named ones,
+// it fails when a name is not registered.
+func ResolveMiddlewares(global []Middleware, named map[string]Middleware, names ...string) ([]Middleware, error) {
+	ret := make([]Middleware, 0, len(global)+len(names))
+	ret = append(ret, global...)
+	for _, name := range names {
+		m, ok := named[name]
+		if !ok {
+			return nil, fmt.Errorf("middleware %q is not registered", name)
+		}
+		ret = append(ret, m)
+	}
+	return ret, nil
+}
@@ -0,0 +1,78 @@
+package httper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// traceMiddleware appends name to the X-Trace header of the response.
+func traceMiddleware(name string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Trace", name)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestChain(t *testing.T) {
+	tests := []struct {
+		name string
+		m    []Middleware
+		want []string
+	}{
+		{"none", nil, []string{"handler"}},
+		{"one", []Middleware{traceMiddleware("a")}, []string{"a", "handler"}},
+		{"outermost first", []Middleware{traceMiddleware("a"), traceMiddleware("b")}, []string{"a", "b", "handler"}},
+		{"nil skipped", []Middleware{traceMiddleware("a"), nil, traceMiddleware("c")}, []string{"a", "c", "handler"}},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			h := Chain(traceMiddleware("handler")(http.NotFoundHandler()), tc.m...)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+			if got := w.Header()["X-Trace"]; !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("want the order %v, got %v", strings.Join(tc.want, ","), strings.Join(got, ","))
+			}
+		})
+	}
+}
+
+func TestResolveMiddlewares(t *testing.T) {
+	global := []Middleware{traceMiddleware("global")}
+	named := map[string]Middleware{"a": traceMiddleware("a"), "b": traceMiddleware("b")}
+	tests := []struct {
+		name    string
+		names   []string
+		want    []string
+		wantErr bool
+	}{
+		{"global only", nil, []string{"global", "handler"}, false},
+		{"named in order", []string{"b", "a"}, []string{"global", "b", "a", "handler"}, false},
+		{"unknown name", []string{"a", "c"}, nil, true},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := ResolveMiddlewares(global, named, tc.names...)
+			if tc.wantErr {
+				if err == nil || !strings.Contains(err.Error(), `"c"`) {
+					t.Fatalf("want an error naming the middleware c, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			w := httptest.NewRecorder()
+			Chain(traceMiddleware("handler")(http.NotFoundHandler()), m...).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+			if got := w.Header()["X-Trace"]; !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("want the order %v, got %v", strings.Join(tc.want, ","), strings.Join(got, ","))
+			}
+		})
+	}
+}
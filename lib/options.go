@@ -0,0 +1,193 @@
+package httper
+
+import (
+	"reflect"
+
+	"github.com/gorilla/sessions"
+)
+
+// Options of a generated httper.
+type Options struct {
+	Finalizer Finalizer
+	Dataer    DataerProvider
+	Cookier   CookieProvider
+	Sessioner SessionProvider
+	// Authenticator authenticates the requests of the handlers requiring a principal.
+	Authenticator Authenticator
+	// Authorizer authorizes the principal of the handlers requiring roles or permissions.
+	Authorizer Authorizer
+	// BodyDecoder decodes the reqBody parameters which are not an io.Reader.
+	BodyDecoder BodyDecoder
+	// Middlewares wrap every handler, the first one is the outermost.
+	Middlewares []Middleware
+	// NamedMiddlewares wrap the handlers having their name in a @middleware annotation.
+	NamedMiddlewares map[string]Middleware
+	// MaxMemory to parse the multipart forms, it is set to copies of the Dataer
+	// and the BodyDecoder having a SetMaxMemory(int64) method.
+	MaxMemory int64
+	// CookieCodec decodes the cookie prefixed data of the Dataer when it is set.
+	CookieCodec *CookieCodec
+}
+
+// Option configures the Options of a generated httper.
+type Option func(*Options)
+
+// Apply opts to o.
+func (o *Options) Apply(opts ...Option) *Options {
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	if o.MaxMemory > 0 {
+		// the limit is set to copies, the providers may be shared.
+		if d, ok := copyPointed(o.Dataer).(interface {
+			DataerProvider
+			SetMaxMemory(int64)
+		}); ok {
+			d.SetMaxMemory(o.MaxMemory)
+			o.Dataer = d
+		}
+		if d, ok := copyPointed(o.BodyDecoder).(interface {
+			BodyDecoder
+			SetMaxMemory(int64)
+		}); ok {
+			d.SetMaxMemory(o.MaxMemory)
+			o.BodyDecoder = d
+		}
+	}
+	if o.CookieCodec != nil && o.Dataer != nil {
+		o.Dataer = NewSecureCookieDataerProvider(o.Dataer, o.CookieCodec)
+	}
+	return o
+}
+
+// copyPointed returns a shallow copy of the value pointed by x,
+// x when it is not a pointer.
+func copyPointed(x interface{}) interface{} {
+	v := reflect.ValueOf(x)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return x
+	}
+	c := reflect.New(v.Elem().Type())
+	c.Elem().Set(v.Elem())
+	return c.Interface()
+}
+
+// WithFinalizer configures the Finalizer, nil is ignored.
+func WithFinalizer(f Finalizer) Option {
+	return func(o *Options) {
+		if f != nil {
+			o.Finalizer = f
+		}
+	}
+}
+
+// WithDataer configures the DataerProvider, nil is ignored.
+func WithDataer(d DataerProvider) Option {
+	return func(o *Options) {
+		if d != nil {
+			o.Dataer = d
+		}
+	}
+}
+
+// WithCookier configures the CookieProvider, nil is ignored.
+func WithCookier(c CookieProvider) Option {
+	return func(o *Options) {
+		if c != nil {
+			o.Cookier = c
+		}
+	}
+}
+
+// WithSessioner configures the SessionProvider, nil is ignored.
+func WithSessioner(s SessionProvider) Option {
+	return func(o *Options) {
+		if s != nil {
+			o.Sessioner = s
+		}
+	}
+}
+
+// WithAuthenticator configures the Authenticator, nil is ignored.
+func WithAuthenticator(a Authenticator) Option {
+	return func(o *Options) {
+		if a != nil {
+			o.Authenticator = a
+		}
+	}
+}
+
+// WithAuthorizer configures the Authorizer, nil is ignored.
+func WithAuthorizer(a Authorizer) Option {
+	return func(o *Options) {
+		if a != nil {
+			o.Authorizer = a
+		}
+	}
+}
+
+// WithBodyDecoder configures the BodyDecoder, nil is ignored.
+func WithBodyDecoder(d BodyDecoder) Option {
+	return func(o *Options) {
+		if d != nil {
+			o.BodyDecoder = d
+		}
+	}
+}
+
+// WithMaxMemory configures the memory limit to parse the multipart forms.
+func WithMaxMemory(n int64) Option {
+	return func(o *Options) {
+		o.MaxMemory = n
+	}
+}
+
+// WithMiddleware appends m to the middlewares.
+func WithMiddleware(m ...Middleware) Option {
+	return func(o *Options) {
+		o.Middlewares = append(o.Middlewares, m...)
+	}
+}
+
+// WithSecureCookies configures the Cookier to sign and encrypt the cookies with p,
+// the cookie prefixed parameters are decoded with its codec.
+func WithSecureCookies(p *SecureCookieProvider) Option {
+	return func(o *Options) {
+		if p != nil {
+			o.Cookier = p
+			o.CookieCodec = p.Codec
+		}
+	}
+}
+
+// WithStdSessionStore configures the SessionProvider and the DataerProvider
+// of the std mode to read and write the sessions of store.
+func WithStdSessionStore(store SessionStore) Option {
+	return func(o *Options) {
+		o.Sessioner = NewStoreSessionProvider(store)
+		o.Dataer = NewStdHTTPDataProvider(store)
+	}
+}
+
+// WithNamedMiddleware registers m as name,
+// it wraps the handlers of the methods annotated with @middleware name.
+// The constructors of the httpers panic on the names not registered.
+func WithNamedMiddleware(name string, m Middleware) Option {
+	return func(o *Options) {
+		if o.NamedMiddlewares == nil {
+			o.NamedMiddlewares = map[string]Middleware{}
+		}
+		o.NamedMiddlewares[name] = m
+	}
+}
+
+// WithGorillaSessionStore configures the SessionProvider and the DataerProvider
+// to read and write the session name of store.
+func WithGorillaSessionStore(store sessions.Store, name string) Option {
+	return func(o *Options) {
+		o.Sessioner = NewGorillaSessionProvider(store, name)
+		o.Dataer = NewGorillaHTTPDataProvider(store, name)
+	}
+}
@@ -0,0 +1,27 @@
+package httper
+
+import "testing"
+
+func TestOptionsMaxMemory(t *testing.T) {
+	dataer := NewStdHTTPDataProvider(nil)
+	decoder := &ContentBodyDecoder{}
+	o := (&Options{Dataer: dataer, BodyDecoder: decoder}).Apply(WithMaxMemory(10))
+	if got, ok := o.Dataer.(*StdHTTPDataProvider); !ok || got.MaxMemory != 10 {
+		t.Fatalf("want a Dataer of MaxMemory 10, got %#v", o.Dataer)
+	}
+	if got, ok := o.BodyDecoder.(*ContentBodyDecoder); !ok || got.MaxMemory != 10 {
+		t.Fatalf("want a BodyDecoder of MaxMemory 10, got %#v", o.BodyDecoder)
+	}
+	if dataer.MaxMemory != 0 || decoder.MaxMemory != 0 {
+		t.Fatalf("the providers of the caller must not be modified, got %v %v", dataer.MaxMemory, decoder.MaxMemory)
+	}
+
+	gorilla := NewGorillaHTTPDataProvider(nil, "s")
+	o = (&Options{Dataer: gorilla}).Apply(WithMaxMemory(10))
+	if got, ok := o.Dataer.(*GorillaHTTPDataProvider); !ok || got.MaxMemory != 10 || got.Name != "s" {
+		t.Fatalf("want a gorilla Dataer of MaxMemory 10, got %#v", o.Dataer)
+	}
+	if gorilla.MaxMemory != 0 {
+		t.Fatalf("the provider of the caller must not be modified, got %v", gorilla.MaxMemory)
+	}
+}
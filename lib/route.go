@@ -0,0 +1,88 @@
+package httper
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Route describes an http route of an httper.
+type Route struct {
+	Name    string
+	Path    string
+	Methods []string
+	Handler http.HandlerFunc
+}
+
+// Routes is a list of Route.
+type Routes []Route
+
+// Get the route named name.
+func (r Routes) Get(name string) *Route {
+	for i, route := range r {
+		if route.Name == name {
+			return &r[i]
+		}
+	}
+	return nil
+}
+
+// URL builds the url of the route named name,
+// pairs is a list of key/value of the route variables.
+func (r Routes) URL(name string, pairs ...string) (*url.URL, error) {
+	route := r.Get(name)
+	if route == nil {
+		return nil, fmt.Errorf("route %q not found", name)
+	}
+	return route.URL(pairs...)
+}
+
+// URL builds the url of the route,
+// pairs is a list of key/value of the route variables.
+func (r Route) URL(pairs ...string) (*url.URL, error) {
+	if len(pairs)%2 != 0 {
+		return nil, fmt.Errorf("route %q: odd number of pairs %v", r.Name, pairs)
+	}
+	vars := map[string]string{}
+	for i := 0; i < len(pairs); i += 2 {
+		vars[pairs[i]] = pairs[i+1]
+	}
+	// path is the decoded form of raw.
+	path, raw := "", ""
+	rest := r.Path
+	for {
+		start := strings.Index(rest, "{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(rest[start:], "}")
+		if end < 0 {
+			return nil, fmt.Errorf("route %q: unbalanced braces in %q", r.Name, r.Path)
+		}
+		end += start
+		// {name} or {name:pattern}
+		varName := strings.SplitN(rest[start+1:end], ":", 2)[0]
+		val, ok := vars[varName]
+		if !ok {
+			return nil, fmt.Errorf("route %q: missing variable %q", r.Name, varName)
+		}
+		path += rest[:start] + val
+		raw += rest[:start] + url.PathEscape(val)
+		rest = rest[end+1:]
+	}
+	return &url.URL{Path: path + rest, RawPath: raw + rest}, nil
+}
+
+// AllowMethods responds http 405 when the request method
+// is not one of methods, it returns false in that case.
+func AllowMethods(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, m := range methods {
+		if r.Method == m {
+			return true
+		}
+	}
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	return false
+}
@@ -0,0 +1,101 @@
+package httper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouteURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		pairs   []string
+		want    string
+		wantErr bool
+	}{
+		{"static", "/items", nil, "/items", false},
+		{"variable", "/items/{id}", []string{"id", "12"}, "/items/12", false},
+		{"variables", "/items/{id}/tags/{tag}", []string{"tag", "red", "id", "12"}, "/items/12/tags/red", false},
+		{"pattern", "/items/{id:[0-9]+}", []string{"id", "12"}, "/items/12", false},
+		{"escaped", "/items/{id}", []string{"id", "a b/c"}, "/items/a%20b%2Fc", false},
+		{"extra pairs", "/items", []string{"id", "12"}, "/items", false},
+		{"missing variable", "/items/{id}", []string{"tag", "red"}, "", true},
+		{"odd pairs", "/items/{id}", []string{"id"}, "", true},
+		{"unbalanced", "/items/{id", []string{"id", "12"}, "", true},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := Route{Name: "r", Path: tc.path}.URL(tc.pairs...)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("want an error, got %v", u)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := u.EscapedPath(); got != tc.want {
+				t.Fatalf("want the url %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestRoutesURL(t *testing.T) {
+	routes := Routes{
+		{Name: "item.get", Path: "/items/{id}"},
+		{Name: "item.list", Path: "/items"},
+	}
+	u, err := routes.URL("item.get", "id", "3")
+	if err != nil || u.Path != "/items/3" {
+		t.Fatalf("want the url /items/3, got %v %v", u, err)
+	}
+	if _, err := routes.URL("item.get"); err == nil {
+		t.Fatalf("want an error of a missing variable")
+	}
+	if _, err := routes.URL("item.delete"); err == nil {
+		t.Fatalf("want an error of an unknown route")
+	}
+	if r := routes.Get("item.list"); r == nil || r.Path != "/items" {
+		t.Fatalf("want the route item.list, got %v", r)
+	}
+}
+
+func TestAllowMethods(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		methods   []string
+		want      bool
+		wantAllow string
+	}{
+		{"allowed", "PUT", []string{"PUT", "PATCH"}, true, ""},
+		{"other allowed", "PATCH", []string{"PUT", "PATCH"}, true, ""},
+		{"not allowed", "GET", []string{"PUT", "PATCH"}, false, "PUT, PATCH"},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			got := AllowMethods(w, httptest.NewRequest(tc.method, "/", nil), tc.methods...)
+			if got != tc.want {
+				t.Fatalf("want %v, got %v", tc.want, got)
+			}
+			if tc.want {
+				if w.Code != http.StatusOK || w.Body.Len() > 0 {
+					t.Fatalf("want the response untouched, got %v %q", w.Code, w.Body.String())
+				}
+				return
+			}
+			if w.Code != http.StatusMethodNotAllowed {
+				t.Fatalf("want the status %v, got %v", http.StatusMethodNotAllowed, w.Code)
+			}
+			if allow := w.Header().Get("Allow"); allow != tc.wantAllow {
+				t.Fatalf("want the Allow header %q, got %q", tc.wantAllow, allow)
+			}
+		})
+	}
+}
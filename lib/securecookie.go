@@ -0,0 +1,311 @@
+package httper
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCookie is returned when a cookie can not be verified or decrypted.
+var ErrInvalidCookie = errors.New("invalid cookie")
+
+// ErrNoCookieKey is returned when a CookieCodec has no hash key.
+var ErrNoCookieKey = errors.New("no cookie hash key")
+
+// ErrExpiredCookie is returned when a cookie is older than the MaxAge of a CookieCodec.
+var ErrExpiredCookie = errors.New("expired cookie")
+
+// DefaultCookieMaxAge is the MaxAge of the CookieCodecs of NewCookieCodec.
+var DefaultCookieMaxAge = 30 * 24 * time.Hour
+
+// CookieCodec signs and optionally encrypts cookie values.
+// The first keys are used to encode, all of them are tried to decode,
+// thus the keys can be rotated by prepending a new one.
+// The signed values embed their time of issue to expire after MaxAge.
+type CookieCodec struct {
+	// HashKeys sign the values with HMAC-SHA256.
+	HashKeys [][]byte
+	// BlockKeys encrypt the values with AES-GCM when they are set,
+	// they must be 16, 24 or 32 bytes long.
+	BlockKeys [][]byte
+	// MaxAge of the values, they never expire when it is 0.
+	MaxAge time.Duration
+	now    func() time.Time
+}
+
+// NewCookieCodec constructs a CookieCodec signing with hashKeys,
+// its values expire after DefaultCookieMaxAge.
+func NewCookieCodec(hashKeys ...[]byte) *CookieCodec {
+	return &CookieCodec{HashKeys: hashKeys, MaxAge: DefaultCookieMaxAge}
+}
+
+func (c *CookieCodec) clock() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+// Encode the value of the cookie name,
+// it is formatted as issued-at.payload.signature.
+func (c *CookieCodec) Encode(name, value string) (string, error) {
+	if len(c.HashKeys) == 0 {
+		return "", ErrNoCookieKey
+	}
+	b := []byte(value)
+	if len(c.BlockKeys) > 0 {
+		gcm, err := newGCM(c.BlockKeys[0])
+		if err != nil {
+			return "", err
+		}
+		nonce := make([]byte, gcm.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return "", err
+		}
+		b = gcm.Seal(nonce, nonce, b, []byte(name))
+	}
+	issued := strconv.FormatInt(c.clock().Unix(), 10)
+	payload := issued + "." + base64.RawURLEncoding.EncodeToString(b)
+	sig := cookieMAC(c.HashKeys[0], name, payload)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// Decode the value of the cookie name, it returns ErrExpiredCookie
+// when the value was issued more than MaxAge ago.
+func (c *CookieCodec) Decode(name, value string) (string, error) {
+	parts := strings.SplitN(value, ".", 3)
+	if len(parts) != 3 {
+		return "", ErrInvalidCookie
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrInvalidCookie
+	}
+	payload := parts[0] + "." + parts[1]
+	verified := false
+	for _, key := range c.HashKeys {
+		if hmac.Equal(sig, cookieMAC(key, name, payload)) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return "", ErrInvalidCookie
+	}
+	issued, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return "", ErrInvalidCookie
+	}
+	if c.MaxAge > 0 && c.clock().Sub(time.Unix(issued, 0)) > c.MaxAge {
+		return "", ErrExpiredCookie
+	}
+	b, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrInvalidCookie
+	}
+	if len(c.BlockKeys) == 0 {
+		return string(b), nil
+	}
+	for _, key := range c.BlockKeys {
+		gcm, err := newGCM(key)
+		if err != nil || len(b) < gcm.NonceSize() {
+			continue
+		}
+		nonce, sealed := b[:gcm.NonceSize()], b[gcm.NonceSize():]
+		if plain, err := gcm.Open(nil, nonce, sealed, []byte(name)); err == nil {
+			return string(plain), nil
+		}
+	}
+	return "", ErrInvalidCookie
+}
+
+func cookieMAC(key []byte, name, payload string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(name + "|" + payload))
+	return mac.Sum(nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// CookieOptions are the attributes of the cookies written by a SecureCookie.
+type CookieOptions struct {
+	Path     string
+	Domain   string
+	MaxAge   int
+	Secure   bool
+	HttpOnly bool
+	SameSite http.SameSite
+}
+
+// DefaultCookieOptions are the attributes of the cookies of NewSecureCookieProvider.
+var DefaultCookieOptions = CookieOptions{
+	Path:     "/",
+	Secure:   true,
+	HttpOnly: true,
+	SameSite: http.SameSiteLaxMode,
+}
+
+// SecureCookieProvider instancatiates SecureCookies.
+type SecureCookieProvider struct {
+	Codec   *CookieCodec
+	Options CookieOptions
+}
+
+// NewSecureCookieProvider constructs a SecureCookieProvider
+// of codec with the DefaultCookieOptions.
+func NewSecureCookieProvider(codec *CookieCodec) *SecureCookieProvider {
+	return &SecureCookieProvider{Codec: codec, Options: DefaultCookieOptions}
+}
+
+// Make returns a SecureCookie
+func (c SecureCookieProvider) Make(w http.ResponseWriter, r *http.Request) Cookier {
+	return &SecureCookie{w, r, c.Codec, c.Options}
+}
+
+// SecureCookie helps to deal with signed and encrypted cookies.
+type SecureCookie struct {
+	w       http.ResponseWriter
+	r       *http.Request
+	codec   *CookieCodec
+	options CookieOptions
+}
+
+// Get a cookie value, it is empty when the cookie is invalid.
+func (c SecureCookie) Get(name string) string {
+	cookie := c.GetCookie(name)
+	if cookie == nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+// GetCookie returns a decoded cookie, it is nil when the cookie is invalid.
+func (c SecureCookie) GetCookie(name string) *http.Cookie {
+	cookie, _ := c.r.Cookie(name)
+	if cookie == nil {
+		return nil
+	}
+	value, err := c.codec.Decode(name, cookie.Value)
+	if err != nil {
+		return nil
+	}
+	ret := *cookie
+	ret.Value = value
+	return &ret
+}
+
+// Set a cookie value, it returns nil when the value can not be encoded.
+func (c SecureCookie) Set(name string, value string, t ...time.Time) *http.Cookie {
+	cookie := &http.Cookie{Name: name, Value: value}
+	if len(t) > 0 {
+		cookie.Expires = t[0]
+	}
+	if c.setCookie(cookie) != nil {
+		return nil
+	}
+	return cookie
+}
+
+// SetCookie encodes the value of cookie and sets it,
+// its unset attributes are taken from the options.
+func (c SecureCookie) SetCookie(cookie *http.Cookie) {
+	c.setCookie(cookie)
+}
+
+func (c SecureCookie) setCookie(cookie *http.Cookie) error {
+	value, err := c.codec.Encode(cookie.Name, cookie.Value)
+	if err != nil {
+		return err
+	}
+	ret := *cookie
+	ret.Value = value
+	if ret.Path == "" {
+		ret.Path = c.options.Path
+	}
+	if ret.Domain == "" {
+		ret.Domain = c.options.Domain
+	}
+	if ret.MaxAge == 0 {
+		ret.MaxAge = c.options.MaxAge
+	}
+	if ret.SameSite == 0 {
+		ret.SameSite = c.options.SameSite
+	}
+	ret.Secure = ret.Secure || c.options.Secure
+	ret.HttpOnly = ret.HttpOnly || c.options.HttpOnly
+	http.SetCookie(c.w, &ret)
+	return nil
+}
+
+// SecureCookieDataerProvider decodes the cookie prefixed data
+// of its DataerProvider with Codec.
+type SecureCookieDataerProvider struct {
+	DataerProvider
+	Codec *CookieCodec
+}
+
+// NewSecureCookieDataerProvider constructs a SecureCookieDataerProvider.
+func NewSecureCookieDataerProvider(provider DataerProvider, codec *CookieCodec) *SecureCookieDataerProvider {
+	return &SecureCookieDataerProvider{DataerProvider: provider, Codec: codec}
+}
+
+// Make returns a Dataer
+func (c SecureCookieDataerProvider) Make(w http.ResponseWriter, r *http.Request) Dataer {
+	return &secureCookieDataer{c.DataerProvider.Make(w, r), r, c.Codec}
+}
+
+type secureCookieDataer struct {
+	Dataer
+	r     *http.Request
+	codec *CookieCodec
+}
+
+// Get a string
+func (c secureCookieDataer) Get(prefix, name string) string {
+	if prefix != "cookie" {
+		return c.Dataer.Get(prefix, name)
+	}
+	if cookie, _ := c.r.Cookie(name); cookie != nil {
+		value, _ := c.codec.Decode(name, cookie.Value)
+		return value
+	}
+	return ""
+}
+
+// GetAll strings
+func (c secureCookieDataer) GetAll(prefix, name string) []string {
+	if prefix != "cookie" {
+		return c.Dataer.GetAll(prefix, name)
+	}
+	var ret []string
+	for _, cookie := range c.r.Cookies() {
+		if cookie.Name == name {
+			if value, err := c.codec.Decode(name, cookie.Value); err == nil {
+				ret = append(ret, value)
+			}
+		}
+	}
+	return ret
+}
+
+// GetAny kind of value
+func (c secureCookieDataer) GetAny(prefix, name string) interface{} {
+	if prefix != "cookie" {
+		return c.Dataer.GetAny(prefix, name)
+	}
+	return c.Get(prefix, name)
+}
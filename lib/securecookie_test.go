@@ -0,0 +1,126 @@
+package httper
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCookieCodec(t *testing.T) {
+	now := time.Date(2017, 1, 2, 15, 4, 5, 0, time.UTC)
+	clock := func() time.Time { return now }
+	hashKey, oldHashKey := []byte("hash-key"), []byte("old-hash-key")
+	blockKey, oldBlockKey := []byte("0123456789abcdef"), []byte("fedcba9876543210")
+
+	signed := &CookieCodec{HashKeys: [][]byte{hashKey}, now: clock}
+	encrypted := &CookieCodec{HashKeys: [][]byte{hashKey}, BlockKeys: [][]byte{blockKey}, now: clock}
+	oldSigned := &CookieCodec{HashKeys: [][]byte{oldHashKey}, now: clock}
+	oldEncrypted := &CookieCodec{HashKeys: [][]byte{oldHashKey}, BlockKeys: [][]byte{oldBlockKey}, now: clock}
+	rotated := &CookieCodec{
+		HashKeys:  [][]byte{hashKey, oldHashKey},
+		BlockKeys: [][]byte{blockKey, oldBlockKey},
+		now:       clock,
+	}
+	expiring := &CookieCodec{HashKeys: [][]byte{hashKey}, MaxAge: time.Hour, now: clock}
+	expired := &CookieCodec{HashKeys: [][]byte{hashKey}, MaxAge: time.Hour,
+		now: func() time.Time { return now.Add(2 * time.Hour) }}
+	neverExpires := &CookieCodec{HashKeys: [][]byte{hashKey},
+		now: func() time.Time { return now.Add(24 * 365 * time.Hour) }}
+
+	tests := []struct {
+		name    string
+		encoder *CookieCodec
+		decoder *CookieCodec
+		tamper  func(string) string
+		decName string
+		wantErr error
+	}{
+		{name: "signed", encoder: signed, decoder: signed},
+		{name: "encrypted", encoder: encrypted, decoder: encrypted},
+		{name: "tampered payload", encoder: signed, decoder: signed, wantErr: ErrInvalidCookie,
+			tamper: func(v string) string {
+				parts := strings.Split(v, ".")
+				parts[1] = "x" + parts[1]
+				return strings.Join(parts, ".")
+			}},
+		{name: "tampered issue date", encoder: signed, decoder: signed, wantErr: ErrInvalidCookie,
+			tamper: func(v string) string { return "9" + v }},
+		{name: "tampered signature", encoder: signed, decoder: signed, wantErr: ErrInvalidCookie,
+			tamper: func(v string) string { return v[:len(v)-2] + "xx" }},
+		{name: "malformed", encoder: signed, decoder: signed, wantErr: ErrInvalidCookie,
+			tamper: func(v string) string { return "value" }},
+		{name: "other name", encoder: signed, decoder: signed, decName: "other", wantErr: ErrInvalidCookie},
+		{name: "encrypted other name", encoder: encrypted, decoder: &CookieCodec{
+			HashKeys: [][]byte{hashKey}, BlockKeys: [][]byte{blockKey}}, decName: "other", wantErr: ErrInvalidCookie},
+		{name: "unknown hash key", encoder: oldSigned, decoder: signed, wantErr: ErrInvalidCookie},
+		{name: "rotated hash key", encoder: oldSigned, decoder: &CookieCodec{HashKeys: [][]byte{hashKey, oldHashKey}}},
+		{name: "rotated keys", encoder: oldEncrypted, decoder: rotated},
+		{name: "unknown block key", encoder: &CookieCodec{HashKeys: [][]byte{hashKey}, BlockKeys: [][]byte{oldBlockKey}},
+			decoder: encrypted, wantErr: ErrInvalidCookie},
+		{name: "not expired", encoder: expiring, decoder: expiring},
+		{name: "expired", encoder: expiring, decoder: expired, wantErr: ErrExpiredCookie},
+		{name: "without max age", encoder: signed, decoder: neverExpires},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := tc.encoder.Encode("name", "hello")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(v, "hello") && len(tc.encoder.BlockKeys) > 0 {
+				t.Fatalf("want an encrypted value, got %q", v)
+			}
+			if tc.tamper != nil {
+				v = tc.tamper(v)
+			}
+			decName := tc.decName
+			if decName == "" {
+				decName = "name"
+			}
+			got, err := tc.decoder.Decode(decName, v)
+			if err != tc.wantErr {
+				t.Fatalf("want error %v, got %v", tc.wantErr, err)
+			}
+			if err == nil && got != "hello" {
+				t.Fatalf("want hello, got %q", got)
+			}
+		})
+	}
+}
+
+func TestCookieCodecNoKey(t *testing.T) {
+	if _, err := (&CookieCodec{}).Encode("name", "hello"); err != ErrNoCookieKey {
+		t.Fatalf("want ErrNoCookieKey, got %v", err)
+	}
+	if c := NewCookieCodec([]byte("k")); c.MaxAge != DefaultCookieMaxAge {
+		t.Fatalf("want the DefaultCookieMaxAge, got %v", c.MaxAge)
+	}
+}
+
+func TestSecureCookie(t *testing.T) {
+	p := NewSecureCookieProvider(NewCookieCodec([]byte("key")))
+	w := httptest.NewRecorder()
+	p.Make(w, httptest.NewRequest("GET", "/", nil)).Set("user", "bob")
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Value == "bob" || !cookies[0].Secure || !cookies[0].HttpOnly {
+		t.Fatalf("want an encoded, secure, http only cookie, got %+v", cookies)
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.AddCookie(cookies[0])
+	if got := p.Make(httptest.NewRecorder(), r).Get("user"); got != "bob" {
+		t.Fatalf("want bob, got %q", got)
+	}
+	d := NewSecureCookieDataerProvider(&StdHTTPDataProvider{}, p.Codec).Make(httptest.NewRecorder(), r)
+	if got := d.Get("cookie", "user"); got != "bob" {
+		t.Fatalf("want the decoded cookie parameter bob, got %q", got)
+	}
+
+	r = httptest.NewRequest("GET", "/", nil)
+	cookies[0].Value = "bob"
+	r.AddCookie(cookies[0])
+	if got := p.Make(httptest.NewRecorder(), r).Get("user"); got != "" {
+		t.Fatalf("want an invalid cookie ignored, got %q", got)
+	}
+}
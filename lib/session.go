@@ -1,7 +1,12 @@
 package httper
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/gorilla/sessions"
 )
@@ -12,6 +17,7 @@ type Sessionner interface {
 	GetAny(name string) interface{}
 	Set(name string, value string)
 	SetAny(name string, value interface{})
+	Save() error
 }
 
 // SessionProvider is a Sessionner factory.
@@ -50,14 +56,26 @@ func (c VoidSession) SetAny(name string, value interface{}) {}
 // Set a string.
 func (c VoidSession) Set(name, value string) {}
 
+// Save does nothing.
+func (c VoidSession) Save() error { return nil }
+
 // GorillaSessionProvider instancatiates SessionProvider.
 type GorillaSessionProvider struct {
 	Name  string
 	store sessions.Store
 }
 
+// NewGorillaSessionProvider constructs a GorillaSessionProvider
+// of the session name within store.
+func NewGorillaSessionProvider(store sessions.Store, name string) *GorillaSessionProvider {
+	return &GorillaSessionProvider{Name: name, store: store}
+}
+
 // Make returns a SessionProvider
 func (c GorillaSessionProvider) Make(w http.ResponseWriter, r *http.Request) Sessionner {
+	if c.store == nil {
+		return &VoidSession{w, r}
+	}
 	s, _ := c.store.Get(r, c.Name)
 	//doc:
 	// Get a session. We're ignoring the error resulted from decoding an
@@ -74,7 +92,8 @@ type GorillaSession struct {
 
 // Get a string
 func (c GorillaSession) Get(name string) string {
-	return c.GetAny(name).(string)
+	s, _ := c.GetAny(name).(string)
+	return s
 }
 
 // GetAny returns any kind of value.
@@ -94,3 +113,263 @@ func (c GorillaSession) SetAny(name string, value interface{}) {
 func (c GorillaSession) Set(name, value string) {
 	c.SetAny(name, value)
 }
+
+// Save the session.
+func (c GorillaSession) Save() error {
+	return c.session.Save(c.r, c.w)
+}
+
+// SessionStore loads and saves the values of the sessions
+// of a standard http handling.
+type SessionStore interface {
+	Load(r *http.Request) map[string]interface{}
+	Save(w http.ResponseWriter, r *http.Request, values map[string]interface{}) error
+}
+
+// StoreSessionProvider instancatiates SessionProvider of a SessionStore.
+type StoreSessionProvider struct {
+	Store SessionStore
+}
+
+// NewStoreSessionProvider constructs a StoreSessionProvider of store.
+func NewStoreSessionProvider(store SessionStore) *StoreSessionProvider {
+	return &StoreSessionProvider{Store: store}
+}
+
+// Make returns a SessionProvider
+func (c StoreSessionProvider) Make(w http.ResponseWriter, r *http.Request) Sessionner {
+	if c.Store == nil {
+		return &VoidSession{w, r}
+	}
+	return &StoreSession{w: w, r: r, store: c.Store, values: c.Store.Load(r)}
+}
+
+// StoreSession is a session of a SessionStore.
+type StoreSession struct {
+	w        http.ResponseWriter
+	r        *http.Request
+	store    SessionStore
+	values   map[string]interface{}
+	modified bool
+}
+
+// Get a string
+func (c StoreSession) Get(name string) string {
+	s, _ := c.GetAny(name).(string)
+	return s
+}
+
+// GetAny returns any kind of value.
+func (c StoreSession) GetAny(name string) interface{} {
+	if val, ok := c.values[name]; ok {
+		return val
+	}
+	return nil
+}
+
+// SetAny kind of value.
+func (c *StoreSession) SetAny(name string, value interface{}) {
+	c.values[name] = value
+	c.modified = true
+}
+
+// Set a string.
+func (c *StoreSession) Set(name, value string) {
+	c.SetAny(name, value)
+}
+
+// Save the session when it was modified.
+func (c *StoreSession) Save() error {
+	if !c.modified {
+		return nil
+	}
+	return c.store.Save(c.w, c.r, c.values)
+}
+
+// DefaultSessionTTL is the lifetime of the idle sessions of a MemorySessionStore.
+var DefaultSessionTTL = 24 * time.Hour
+
+// MemorySessionStore keeps the sessions in memory,
+// the cookie Name identifies the session of a request.
+// A session expires after TTL without being saved.
+type MemorySessionStore struct {
+	Name string
+	// TTL of the idle sessions, DefaultSessionTTL when it is 0.
+	TTL time.Duration
+	// Options are the attributes of the session cookie.
+	Options      CookieOptions
+	mu           sync.Mutex
+	sessions     map[string]memorySession
+	lastEviction time.Time
+	now          func() time.Time
+}
+
+type memorySession struct {
+	values  map[string]interface{}
+	expires time.Time
+}
+
+// NewMemorySessionStore constructs a MemorySessionStore identified by the cookie name
+// with the DefaultCookieOptions.
+func NewMemorySessionStore(name string) *MemorySessionStore {
+	return &MemorySessionStore{
+		Name:     name,
+		Options:  DefaultCookieOptions,
+		sessions: map[string]memorySession{},
+	}
+}
+
+func (s *MemorySessionStore) ttl() time.Duration {
+	if s.TTL > 0 {
+		return s.TTL
+	}
+	return DefaultSessionTTL
+}
+
+func (s *MemorySessionStore) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
+// Load the values of the session of r, an expired session is empty.
+func (s *MemorySessionStore) Load(r *http.Request) map[string]interface{} {
+	ret := map[string]interface{}{}
+	cookie, _ := r.Cookie(s.Name)
+	if cookie == nil {
+		return ret
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[cookie.Value]
+	if !ok {
+		return ret
+	}
+	if !s.clock().Before(session.expires) {
+		delete(s.sessions, cookie.Value)
+		return ret
+	}
+	for k, v := range session.values {
+		ret[k] = v
+	}
+	return ret
+}
+
+// Save the values of the session of r and extend its lifetime,
+// a new session identifier is issued when r has none, or an expired one,
+// unless values is empty.
+// The expired sessions are evicted at most once per minute.
+func (s *MemorySessionStore) Save(w http.ResponseWriter, r *http.Request, values map[string]interface{}) error {
+	id := ""
+	if cookie, _ := r.Cookie(s.Name); cookie != nil {
+		id = cookie.Value
+	}
+	now := s.clock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sessions == nil {
+		s.sessions = map[string]memorySession{}
+	}
+	if now.Sub(s.lastEviction) > time.Minute {
+		s.evict(now)
+	}
+	if session, ok := s.sessions[id]; !ok || !now.Before(session.expires) {
+		if len(values) == 0 {
+			return nil
+		}
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return err
+		}
+		id = hex.EncodeToString(b)
+	}
+	// the cookie lives as long as the session.
+	cookie := sessionCookie(s.Name, id, s.Options)
+	cookie.MaxAge = int(s.ttl() / time.Second)
+	http.SetCookie(w, cookie)
+	session := memorySession{values: map[string]interface{}{}, expires: now.Add(s.ttl())}
+	for k, v := range values {
+		session.values[k] = v
+	}
+	s.sessions[id] = session
+	return nil
+}
+
+// evict the sessions expired at now.
+func (s *MemorySessionStore) evict(now time.Time) {
+	for id, session := range s.sessions {
+		if !now.Before(session.expires) {
+			delete(s.sessions, id)
+		}
+	}
+	s.lastEviction = now
+}
+
+// Len returns the number of sessions in memory.
+func (s *MemorySessionStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sessions)
+}
+
+// SignedCookieSessionStore keeps the sessions in the cookie Name,
+// its values are encoded to json, then signed, and encrypted, by Codec.
+type SignedCookieSessionStore struct {
+	Name  string
+	Codec *CookieCodec
+	// Options are the attributes of the session cookie.
+	Options CookieOptions
+}
+
+// NewSignedCookieSessionStore constructs a SignedCookieSessionStore
+// writing the cookie name encoded by codec with the DefaultCookieOptions.
+func NewSignedCookieSessionStore(name string, codec *CookieCodec) *SignedCookieSessionStore {
+	return &SignedCookieSessionStore{Name: name, Codec: codec, Options: DefaultCookieOptions}
+}
+
+// Load the values of the session of r,
+// a cookie which can not be decoded is ignored.
+func (s *SignedCookieSessionStore) Load(r *http.Request) map[string]interface{} {
+	ret := map[string]interface{}{}
+	cookie, _ := r.Cookie(s.Name)
+	if cookie == nil {
+		return ret
+	}
+	value, err := s.Codec.Decode(s.Name, cookie.Value)
+	if err != nil {
+		return ret
+	}
+	if json.Unmarshal([]byte(value), &ret) != nil {
+		return map[string]interface{}{}
+	}
+	return ret
+}
+
+// Save the values of the session to w.
+func (s *SignedCookieSessionStore) Save(w http.ResponseWriter, r *http.Request, values map[string]interface{}) error {
+	b, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	value, err := s.Codec.Encode(s.Name, string(b))
+	if err != nil {
+		return err
+	}
+	http.SetCookie(w, sessionCookie(s.Name, value, s.Options))
+	return nil
+}
+
+// sessionCookie returns the cookie name of value with the attributes of o.
+func sessionCookie(name, value string, o CookieOptions) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     o.Path,
+		Domain:   o.Domain,
+		MaxAge:   o.MaxAge,
+		Secure:   o.Secure,
+		HttpOnly: o.HttpOnly,
+		SameSite: o.SameSite,
+	}
+}
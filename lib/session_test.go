@@ -0,0 +1,134 @@
+package httper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// roundTrip saves values into store with the cookies of prev,
+// it returns the cookies of the response.
+func roundTrip(t *testing.T, store SessionStore, prev []*http.Cookie, values map[string]interface{}) []*http.Cookie {
+	t.Helper()
+	r := httptest.NewRequest("GET", "/", nil)
+	for _, c := range prev {
+		r.AddCookie(c)
+	}
+	w := httptest.NewRecorder()
+	if err := store.Save(w, r, values); err != nil {
+		t.Fatal(err)
+	}
+	return w.Result().Cookies()
+}
+
+// load the session of store with cookies.
+func load(store SessionStore, cookies []*http.Cookie) map[string]interface{} {
+	r := httptest.NewRequest("GET", "/", nil)
+	for _, c := range cookies {
+		r.AddCookie(c)
+	}
+	return store.Load(r)
+}
+
+func TestMemorySessionStore(t *testing.T) {
+	now := time.Date(2017, 1, 2, 15, 4, 5, 0, time.UTC)
+	store := NewMemorySessionStore("sid")
+	store.TTL = time.Hour
+	store.now = func() time.Time { return now }
+
+	cookies := roundTrip(t, store, nil, map[string]interface{}{"user": "bob"})
+	if len(cookies) != 1 || !cookies[0].Secure || !cookies[0].HttpOnly || cookies[0].MaxAge != 3600 {
+		t.Fatalf("want a secure, http only cookie of 1 hour, got %+v", cookies)
+	}
+	if got := load(store, cookies)["user"]; got != "bob" {
+		t.Fatalf("want the user bob, got %v", got)
+	}
+
+	now = now.Add(30 * time.Minute)
+	roundTrip(t, store, cookies, map[string]interface{}{"user": "alice"})
+	now = now.Add(45 * time.Minute)
+	if got := load(store, cookies)["user"]; got != "alice" {
+		t.Fatalf("want a session extended by its save, got %v", got)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if got := load(store, cookies)["user"]; got != nil {
+		t.Fatalf("want an expired session, got %v", got)
+	}
+
+	for i := 0; i < 10; i++ {
+		roundTrip(t, store, nil, map[string]interface{}{"n": i})
+	}
+	now = now.Add(2 * time.Hour)
+	fresh := roundTrip(t, store, cookies, map[string]interface{}{"user": "eve"})
+	if store.Len() != 1 {
+		t.Fatalf("want the expired sessions evicted, got %v sessions", store.Len())
+	}
+	if fresh[0].Value == cookies[0].Value {
+		t.Fatalf("want a new identifier for an expired session")
+	}
+
+	if got := roundTrip(t, store, nil, map[string]interface{}{}); len(got) != 0 || store.Len() != 1 {
+		t.Fatalf("want no session of empty values, got %+v", got)
+	}
+}
+
+func TestStoreSessionSave(t *testing.T) {
+	store := NewMemorySessionStore("sid")
+	provider := NewStoreSessionProvider(store)
+
+	w := httptest.NewRecorder()
+	if err := provider.Make(w, httptest.NewRequest("GET", "/", nil)).Save(); err != nil {
+		t.Fatal(err)
+	}
+	if len(w.Result().Cookies()) != 0 || store.Len() != 0 {
+		t.Fatalf("want an unmodified session not saved, got %v sessions", store.Len())
+	}
+
+	w = httptest.NewRecorder()
+	s := provider.Make(w, httptest.NewRequest("GET", "/", nil))
+	s.Set("user", "bob")
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || store.Len() != 1 {
+		t.Fatalf("want a modified session saved, got %+v", cookies)
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.AddCookie(cookies[0])
+	if got := provider.Make(httptest.NewRecorder(), r).Get("user"); got != "bob" {
+		t.Fatalf("want the user bob, got %q", got)
+	}
+}
+
+func TestSignedCookieSessionStore(t *testing.T) {
+	store := NewSignedCookieSessionStore("s", NewCookieCodec([]byte("key")))
+	cookies := roundTrip(t, store, nil, map[string]interface{}{"user": "bob"})
+	if len(cookies) != 1 || !cookies[0].Secure || !cookies[0].HttpOnly {
+		t.Fatalf("want a secure, http only cookie, got %+v", cookies)
+	}
+
+	tests := []struct {
+		name   string
+		store  *SignedCookieSessionStore
+		cookie func(c http.Cookie) *http.Cookie
+		want   interface{}
+	}{
+		{"valid", store, func(c http.Cookie) *http.Cookie { return &c }, "bob"},
+		{"tampered", store, func(c http.Cookie) *http.Cookie { c.Value = "x" + c.Value; return &c }, nil},
+		{"other key", NewSignedCookieSessionStore("s", NewCookieCodec([]byte("other"))),
+			func(c http.Cookie) *http.Cookie { return &c }, nil},
+		{"renamed", NewSignedCookieSessionStore("t", NewCookieCodec([]byte("key"))),
+			func(c http.Cookie) *http.Cookie { c.Name = "t"; return &c }, nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := load(tc.store, []*http.Cookie{tc.cookie(*cookies[0])})["user"]; got != tc.want {
+				t.Fatalf("want %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package httper
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ValidateTag is the struct tag of the validation rules of a field,
+// `validate:"required,min=1,max=10"`.
+var ValidateTag = "validate"
+
+// Violation is a validation rule violated by a field.
+type Violation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Violations of the validation rules.
+type Violations []Violation
+
+// Check the value v of field against the comma separated rules,
+// required, min=n, max=n, oneof=a b c and pattern=regexp.
+// As the pattern can contain commas, it must be the last rule.
+// min and max apply to the length of strings, slices and maps.
+// A nil pointer is an absent value, only required applies to it.
+func (vs Violations) Check(field string, v interface{}, rules string) Violations {
+	return append(vs, checkRules(field, reflect.ValueOf(v), rules)...)
+}
+
+// Struct checks the fields of the struct v against their ValidateTag,
+// the fields of the embedded and nested structs are checked too.
+func (vs Violations) Struct(field string, v interface{}) Violations {
+	return append(vs, checkStruct(field, reflect.ValueOf(v))...)
+}
+
+// Err returns a *ValidationError of vs, nil when it is empty.
+func (vs Violations) Err() error {
+	if len(vs) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: vs}
+}
+
+// ValidationError is the http 422 Error of the violations of the validation rules.
+type ValidationError struct {
+	Violations Violations
+}
+
+func (e *ValidationError) Error() string {
+	s := []string{}
+	for _, v := range e.Violations {
+		s = append(s, v.Message)
+	}
+	return "validation failed: " + strings.Join(s, ", ")
+}
+
+// StatusCode of the response.
+func (e *ValidationError) StatusCode() int {
+	return http.StatusUnprocessableEntity
+}
+
+// PublicMessage is safe to show to the client.
+func (e *ValidationError) PublicMessage() string {
+	return "validation failed"
+}
+
+// Code is a machine readable code of the error.
+func (e *ValidationError) Code() string {
+	return "validation_failed"
+}
+
+func checkStruct(field string, v reflect.Value) Violations {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	ret := Violations{}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name := validateFieldName(f)
+		if name == "-" {
+			continue
+		}
+		if field != "" && !f.Anonymous {
+			name = field + "." + name
+		} else if f.Anonymous {
+			name = field
+		}
+		if rules := f.Tag.Get(ValidateTag); rules != "" && rules != "-" {
+			ret = append(ret, checkRules(name, v.Field(i), rules)...)
+		}
+		ret = append(ret, checkStruct(name, v.Field(i))...)
+	}
+	return ret
+}
+
+// validateFieldName returns the name of the data of f,
+// its BindTag name, its json tag name, or its name.
+func validateFieldName(f reflect.StructField) string {
+	if tag := f.Tag.Get(BindTag); tag != "" && tag != "-" {
+		_, name := ParseBindTag(tag, f.Name)
+		return name
+	}
+	if name := strings.Split(f.Tag.Get("json"), ",")[0]; name != "" {
+		return name
+	}
+	return f.Name
+}
+
+func checkRules(field string, v reflect.Value, rules string) Violations {
+	ret := Violations{}
+	for rules != "" {
+		rule := rules
+		if !strings.HasPrefix(rule, "pattern=") {
+			parts := strings.SplitN(rules, ",", 2)
+			rule = parts[0]
+			rules = ""
+			if len(parts) > 1 {
+				rules = parts[1]
+			}
+		} else {
+			rules = ""
+		}
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		kv := strings.SplitN(rule, "=", 2)
+		name, arg := kv[0], ""
+		if len(kv) > 1 {
+			arg = kv[1]
+		}
+		if msg := checkRule(v, name, arg); msg != "" {
+			ret = append(ret, Violation{Field: field, Rule: name, Message: field + " " + msg})
+		}
+	}
+	return ret
+}
+
+// checkRule returns the message of the violation of the rule name by v,
+// it is empty when v is valid.
+func checkRule(v reflect.Value, name, arg string) string {
+	if name == "required" {
+		if !v.IsValid() || v.IsZero() {
+			return "is required"
+		}
+		return ""
+	}
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return ""
+	}
+	switch name {
+	case "min", "max":
+		limit, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return fmt.Sprintf("has an invalid %v rule %q", name, arg)
+		}
+		n, isLen, ok := measure(v)
+		if !ok {
+			return ""
+		}
+		if name == "min" && n < limit {
+			if isLen {
+				return fmt.Sprintf("must have a length of at least %v", arg)
+			}
+			return fmt.Sprintf("must be at least %v", arg)
+		}
+		if name == "max" && n > limit {
+			if isLen {
+				return fmt.Sprintf("must have a length of at most %v", arg)
+			}
+			return fmt.Sprintf("must be at most %v", arg)
+		}
+	case "oneof":
+		s := fmt.Sprint(v.Interface())
+		for _, allowed := range strings.Fields(arg) {
+			if s == allowed {
+				return ""
+			}
+		}
+		return fmt.Sprintf("must be one of %v", strings.Join(strings.Fields(arg), ", "))
+	case "pattern":
+		re, err := compilePattern(arg)
+		if err != nil {
+			return fmt.Sprintf("has an invalid pattern rule %q", arg)
+		}
+		if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+			for i := 0; i < v.Len(); i++ {
+				if !re.MatchString(fmt.Sprint(v.Index(i).Interface())) {
+					return fmt.Sprintf("must match %v", arg)
+				}
+			}
+		} else if !re.MatchString(fmt.Sprint(v.Interface())) {
+			return fmt.Sprintf("must match %v", arg)
+		}
+	}
+	return ""
+}
+
+// measure returns the number of v, or its length.
+func measure(v reflect.Value) (n float64, isLen bool, ok bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), false, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), false, true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), false, true
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), true, true
+	}
+	return 0, false, false
+}
+
+var patterns = struct {
+	sync.Mutex
+	m map[string]*regexp.Regexp
+}{m: map[string]*regexp.Regexp{}}
+
+func compilePattern(p string) (*regexp.Regexp, error) {
+	patterns.Lock()
+	defer patterns.Unlock()
+	if re, ok := patterns.m[p]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(p)
+	if err != nil {
+		return nil, err
+	}
+	patterns.m[p] = re
+	return re, nil
+}
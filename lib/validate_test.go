@@ -0,0 +1,142 @@
+package httper
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestViolationsCheck(t *testing.T) {
+	zero := 0
+	three := 3
+	tests := []struct {
+		name  string
+		v     interface{}
+		rules string
+		want  []string
+	}{
+		{"required string", "", "required", []string{"required"}},
+		{"required string set", "x", "required", nil},
+		{"required int", 0, "required", []string{"required"}},
+		{"required nil pointer", (*int)(nil), "required", []string{"required"}},
+		{"required pointed zero", &zero, "required", nil},
+		{"required slice", []string{}, "required", nil},
+		{"required nil slice", []string(nil), "required", []string{"required"}},
+
+		{"min int", 1, "min=2", []string{"min"}},
+		{"min int ok", 2, "min=2", nil},
+		{"min zero int", 0, "min=1", []string{"min"}},
+		{"min pointed zero", &zero, "min=1", []string{"min"}},
+		{"min pointed", &three, "min=1", nil},
+		{"min nil pointer", (*int)(nil), "min=1", nil},
+		{"min uint", uint8(1), "min=2", []string{"min"}},
+		{"min float", 1.5, "min=2", []string{"min"}},
+		{"min string length", "ab", "min=3", []string{"min"}},
+		{"min empty string", "", "min=3", []string{"min"}},
+		{"min slice length", []int{1}, "min=2", []string{"min"}},
+		{"min empty slice", []int{}, "min=2", []string{"min"}},
+		{"min invalid", 1, "min=x", []string{"min"}},
+
+		{"max int", 101, "max=100", []string{"max"}},
+		{"max int ok", 100, "max=100", nil},
+		{"max string length", "abcd", "max=3", []string{"max"}},
+		{"max map length", map[string]int{"a": 1, "b": 2}, "max=1", []string{"max"}},
+		{"min max", 5, "min=1,max=4", []string{"max"}},
+
+		{"oneof", "up", "oneof=asc desc", []string{"oneof"}},
+		{"oneof ok", "desc", "oneof=asc desc", nil},
+		{"oneof int", 3, "oneof=1 2", []string{"oneof"}},
+		{"oneof empty", "", "oneof=asc desc", []string{"oneof"}},
+
+		{"pattern", "a1", "pattern=^[a-z]+$", []string{"pattern"}},
+		{"pattern ok", "ab", "pattern=^[a-z]+$", nil},
+		{"pattern with commas", "aaaa", "pattern=^a{1,3}$", []string{"pattern"}},
+		{"pattern after rules", "aaaa", "required,pattern=^a{1,3}$", []string{"pattern"}},
+		{"pattern slice", []string{"a", "1"}, "pattern=^[a-z]$", []string{"pattern"}},
+		{"pattern invalid", "a", "pattern=(", []string{"pattern"}},
+		{"pattern empty", "", "pattern=^[a-z]+$", []string{"pattern"}},
+		{"pattern nil pointer", (*string)(nil), "pattern=^[a-z]+$", nil},
+
+		{"required and min", "", "required,min=2", []string{"required", "min"}},
+		{"unknown rule", "x", "unknown", nil},
+		{"blank rules", "x", " , ", nil},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := []string{}
+			for _, v := range (Violations{}).Check("f", tc.v, tc.rules) {
+				if v.Field != "f" || v.Message == "" {
+					t.Fatalf("unexpected violation %+v", v)
+				}
+				got = append(got, v.Rule)
+			}
+			if len(got) != len(tc.want) || (len(got) > 0 && !reflect.DeepEqual(got, tc.want)) {
+				t.Fatalf("want the violations of %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+type validateAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type ValidateEmbedded struct {
+	Tag string `httper:"get,tag" validate:"max=2"`
+}
+
+type validateStruct struct {
+	ValidateEmbedded
+	Name     string           `json:"name" validate:"required"`
+	Page     int              `httper:"get,page" validate:"min=1"`
+	Address  validateAddress  `json:"address"`
+	Previous *validateAddress `json:"previous"`
+	Skipped  string           `json:"-" validate:"required"`
+	hidden   string           `validate:"required"`
+}
+
+func TestViolationsStruct(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+		want []string
+	}{
+		{"valid", validateStruct{Name: "x", Page: 1, Address: validateAddress{City: "y"}}, nil},
+		{"nested", validateStruct{Name: "x", Page: 1}, []string{"address.city"}},
+		{"pointed nested", &validateStruct{Name: "x", Page: 1, Address: validateAddress{City: "y"}, Previous: &validateAddress{}},
+			[]string{"previous.city"}},
+		{"bind names", validateStruct{Name: "x", Page: -1, Address: validateAddress{City: "y"},
+			ValidateEmbedded: ValidateEmbedded{Tag: "abc"}}, []string{"tag", "page"}},
+		{"nil pointer", (*validateStruct)(nil), nil},
+		{"not a struct", 1, nil},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := []string{}
+			for _, v := range (Violations{}).Struct("", tc.v) {
+				got = append(got, v.Field)
+			}
+			if len(got) != len(tc.want) || (len(got) > 0 && !reflect.DeepEqual(got, tc.want)) {
+				t.Fatalf("want the violations of %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestViolationsErr(t *testing.T) {
+	if err := (Violations{}).Err(); err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	err := (Violations{}).Check("name", "", "required").Err()
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("want a *ValidationError, got %T", err)
+	}
+	p := NewProblem(err, nil)
+	if p.Status != http.StatusUnprocessableEntity || p.Code != "validation_failed" || len(p.Errors) != 1 {
+		t.Fatalf("unexpected problem %+v", p)
+	}
+}
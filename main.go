@@ -4,14 +4,21 @@ package main
 import (
 	"flag"
 	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
 	"log"
+	"net/http"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
+	"unicode"
 
 	"github.com/mh-cbon/astutil"
 	httper "github.com/mh-cbon/httper/lib"
 	"github.com/mh-cbon/httper/utils"
+	"golang.org/x/tools/go/loader"
 )
 
 var name = "httper"
@@ -25,12 +32,21 @@ func main() {
 	var v bool
 	var outPkg string
 	var mode string
+	var openapi string
+	var client bool
+	var tests bool
 	flag.BoolVar(&help, "help", false, "Show help.")
 	flag.BoolVar(&h, "h", false, "Show help.")
 	flag.BoolVar(&ver, "version", false, "Show version.")
 	flag.BoolVar(&v, "v", false, "Show version.")
 	flag.StringVar(&outPkg, "p", os.Getenv("GOPACKAGE"), "Package name of the new code.")
 	flag.StringVar(&mode, "mode", "std", "Generation mode.")
+	flag.StringVar(&openapi, "openapi", "", "Write an OpenAPI 3 document to this file.")
+	flag.BoolVar(&client, "client", false, "Generate an http client.")
+	flag.BoolVar(&tests, "tests", false, "Generate the tests of the handlers, it requires -interface.")
+	flag.BoolVar(&embedInterface, "interface", false, "Type the embedded value with an interface, generate its mock in a test file.")
+	flag.StringVar(&principalName, "principal-name", principalName, "Name, or name prefix, of the principal parameters.")
+	flag.StringVar(&principalType, "principal-type", "", "Type of the principal parameters, they are matched by their type only.")
 
 	flag.Parse()
 
@@ -46,6 +62,9 @@ func main() {
 		showHelp()
 		return
 	}
+	if tests && !embedInterface {
+		log.Fatal("-tests requires -interface, the handlers are tested with the mock of their embedded value")
+	}
 
 	if flag.NArg() < 1 {
 		panic("wrong usage")
@@ -64,6 +83,7 @@ func main() {
 	}
 
 	filesOut := utils.NewFilesOut("github.com/mh-cbon/" + name)
+	doc := newOpenAPIDoc()
 
 	for _, todo := range todos.Args {
 		if todo.FromPkgPath == "" {
@@ -78,11 +98,43 @@ func main() {
 			fileOut.PkgName = findOutPkg(todo)
 		}
 
-		if err := processType(mode, todo, fileOut); err != nil {
-			log.Println(err)
+		if client {
+			if err := processClient(mode, todo, fileOut); err != nil {
+				log.Fatal(err)
+			}
+		} else if err := processType(mode, todo, fileOut); err != nil {
+			log.Fatal(err)
+		}
+
+		if embedInterface && !client {
+			mockOut := filesOut.Get(strings.TrimSuffix(todo.ToPath, ".go") + "_mock_test.go")
+			mockOut.PkgName = fileOut.PkgName
+			if err := processMock(todo, mockOut); err != nil {
+				log.Println(err)
+			}
+		}
+
+		if tests && !client {
+			testOut := filesOut.Get(strings.TrimSuffix(todo.ToPath, ".go") + "_test.go")
+			testOut.PkgName = fileOut.PkgName
+			if err := processTests(mode, todo, testOut); err != nil {
+				log.Println(err)
+			}
+		}
+
+		if openapi != "" {
+			if err := processOpenAPI(mode, todo, doc); err != nil {
+				log.Println(err)
+			}
 		}
 	}
 	filesOut.Write(out)
+
+	if openapi != "" {
+		if err := doc.Write(openapi); err != nil {
+			log.Println(err)
+		}
+	}
 }
 
 func showVer() {
@@ -94,7 +146,7 @@ func showHelp() {
 	fmt.Println()
 	fmt.Println("Usage")
 	fmt.Println()
-	fmt.Printf("	%v [-p name] [-mode name] [...types]\n\n", name)
+	fmt.Printf("	%v [-p name] [-mode name] [-openapi file] [-client] [-tests] [-interface] [-principal-name name] [-principal-type type] [...types]\n\n", name)
 	fmt.Printf("  types:  A list of types such as src:dst.\n")
 	fmt.Printf("          A type is defined by its package path and its type name,\n")
 	fmt.Printf("          [pkgpath/]name\n")
@@ -105,6 +157,12 @@ func showHelp() {
 	fmt.Printf("          Name can be a valid type identifier such as TypeName, *TypeName, []TypeName \n")
 	fmt.Printf("  -p:     The name of the package output.\n")
 	fmt.Printf("  -mode:  The mode of generation to apply: std|gorilla (defaults to std).\n")
+	fmt.Printf("  -openapi: Write an OpenAPI 3 document of the generated types to this file.\n")
+	fmt.Printf("  -client: Generate an http client of the types instead of their http handlers.\n")
+	fmt.Printf("  -tests: Generate the httptest tests of the handlers in a _test.go file next to them.\n")
+	fmt.Printf("  -interface: Type the embedded value with an interface of the wrapped methods, and generate its recording mock.\n")
+	fmt.Printf("  -principal-name: The name, or name prefix, of the parameters receiving the authenticated principal (defaults to principal).\n")
+	fmt.Printf("  -principal-type: The type of the parameters receiving the authenticated principal, such as *auth.User.\n")
 	fmt.Println()
 }
 
@@ -140,6 +198,7 @@ func processType(mode string, todo utils.TransformArg, fileOut *utils.FileOut) e
 	prog := astutil.GetProgramFast(todo.FromPkgPath)
 	pkg := prog.Package(todo.FromPkgPath)
 	foundMethods := astutil.FindMethods(pkg)
+	findDurationTypes(prog)
 
 	srcConcrete := astutil.GetUnpointedType(srcName)
 	// the json input must provide a key/value for each params.
@@ -159,6 +218,15 @@ func processType(mode string, todo utils.TransformArg, fileOut *utils.FileOut) e
 	var xxHTTPOk = http.StatusOK
 	`)
 
+	// the embedded value is typed with its interface, or with the source type.
+	embedType := srcName
+	if embedInterface {
+		embedType = embedInterfaceName(destName)
+		if err := processInterface(todo, fileOut); err != nil {
+			return err
+		}
+	}
+
 	// Declare the new type
 	fmt.Fprintf(dest, `
 // %v is an httper of %v.
@@ -169,8 +237,13 @@ type %v struct{
 	dataer httper.DataerProvider
 	sessioner httper.SessionProvider
 	finalizer httper.Finalizer
+	bodyDecoder httper.BodyDecoder
+	authenticator httper.Authenticator
+	authorizer httper.Authorizer
+	middlewares []httper.Middleware
+	methodMiddlewares map[string][]httper.Middleware
 }
-		`, destName, srcName, structComment, destName, srcName)
+		`, destName, srcName, structComment, destName, embedType)
 
 	dstStar := astutil.GetPointedType(destName)
 
@@ -180,27 +253,68 @@ type %v struct{
 	sessionFactory := fmt.Sprintf("%T", getSessionProviderFactory(mode))
 	sessionFactory = astutil.GetUnpointedType(sessionFactory)
 
+	// the middlewares of the @middleware annotations are resolved
+	// by the constructor, it panics on the names not registered.
+	resolveMiddlewares := ""
+	for _, m := range wrappedMethods(pkg, srcName) {
+		annotations := getAnnotations(astutil.GetComment(prog, m.Pos()))
+		if names := getAnnotationList(annotations, "middleware"); len(names) > 0 {
+			resolveMiddlewares += fmt.Sprintf("%q: {%v},\n", astutil.MethodName(m), quoteList(names))
+		}
+	}
+	if resolveMiddlewares != "" {
+		resolveMiddlewares = fmt.Sprintf(`for method, names := range map[string][]string{
+		%v} {
+		mws, err := httper.ResolveMiddlewares(o.Middlewares, o.NamedMiddlewares, names...)
+		if err != nil {
+			panic("%v." + method + ": " + err.Error())
+		}
+		ret.methodMiddlewares[method] = mws
+	}
+	`, resolveMiddlewares, astutil.GetUnpointedType(destName))
+	}
+
 	// Make the constructor
 	fmt.Fprintf(dest, `// New%v constructs an httper of %v
 func New%v(embed %v, finalizer httper.Finalizer) *%v {
-	if finalizer == nil {
-		finalizer = &httper.HTTPFinalizer{}
+	return New%vWithOptions(embed, httper.WithFinalizer(finalizer))
+}
+`, destName, srcName, destName, embedType, destName, destName)
+
+	fmt.Fprintf(dest, `// New%vWithOptions constructs an httper of %v configured by opts.
+func New%vWithOptions(embed %v, opts ...httper.Option) *%v {
+	o := &httper.Options{
+		Finalizer: &httper.HTTPFinalizer{},
+		Cookier: &httper.CookieHelperProvider{},
+		Dataer: &%v{},
+		Sessioner: &%v{},
+		BodyDecoder: &httper.ContentBodyDecoder{},
+		Authorizer: &httper.DefaultAuthorizer{},
 	}
+	o.Apply(opts...)
 	ret := &%v{
 		embed: embed,
-		cookier: &httper.CookieHelperProvider{},
-		dataer: &%v{},
-		sessioner: &%v{},
-		finalizer: finalizer,
+		cookier: o.Cookier,
+		dataer: o.Dataer,
+		sessioner: o.Sessioner,
+		finalizer: o.Finalizer,
+		bodyDecoder: o.BodyDecoder,
+		authenticator: o.Authenticator,
+		authorizer: o.Authorizer,
+		middlewares: o.Middlewares,
+		methodMiddlewares: map[string][]httper.Middleware{},
 	}
-  return ret
+	%vreturn ret
 }
-`, destName, srcName, destName, srcName, destName, destName, factory, sessionFactory)
+`, destName, srcName, destName, embedType, destName, factory, sessionFactory, destName, resolveMiddlewares)
+
+	routes := ""
 
 	for _, m := range foundMethods[srcConcrete] {
 		methodName := astutil.MethodName(m)
 		paramNames := astutil.MethodParamNames(m)
 		paramTypes := astutil.MethodParamTypes(m)
+		paramTypesInfo := methodParamTypesInfo(pkg, m)
 
 		// ensure it is desired to facade this method.
 		if astutil.IsExported(methodName) == false {
@@ -211,9 +325,31 @@ func New%v(embed %v, finalizer httper.Finalizer) *%v {
 		}
 
 		comment := astutil.GetComment(prog, m.Pos())
+		annotations := getAnnotations(comment)
+		validations := getAnnotationValues(comment, "validate")
 		comment = makeCommentLines(comment)
 
+		route, err := getRoute(mode, methodName, paramNames, paramTypesInfo, annotations)
+		if err != nil {
+			return fmt.Errorf("%v.%v: %v", srcName, methodName, err)
+		}
+
 		methodInvokation := ""
+		sessionVars := []string{}
+		allowMethods := ""
+		if _, ok := annotations["method"]; ok {
+			allowMethods = fmt.Sprintf(`if !httper.AllowMethods(w, r, %v) {
+return
+}
+`, quoteList(route.Methods))
+		}
+		roles := getAnnotationList(annotations, "roles")
+		permissions := getAnnotationList(annotations, "permission")
+		needsPrincipal := len(roles) > 0 || len(permissions) > 0
+		// the method writes the response itself when it receives w.
+		takesWriter := false
+		// the data of the request is made once, when a parameter reads it.
+		readsData := false
 
 		lParamNames := strings.Split(paramNames, ",")
 		lParamTypes := strings.Split(paramTypes, ",")
@@ -221,7 +357,15 @@ func New%v(embed %v, finalizer httper.Finalizer) *%v {
 			p = strings.TrimSpace(p)
 			paramType := strings.TrimSpace(lParamTypes[i])
 
-			if p == reqBodyVarName {
+			if p == "" {
+				// the method has no parameters.
+
+			} else if p == reqBodyVarName && !isBodyReaderType(paramTypesInfo[i]) {
+				methodInvokation += fmt.Sprintf("var %v %v\n", p, paramType)
+				methodInvokation += fmt.Sprintf("decErr := t.bodyDecoder.Decode(w, r, &%v)\n", p)
+				methodInvokation += handleErr("decErr")
+
+			} else if p == reqBodyVarName {
 				methodInvokation += fmt.Sprintf("%v :=	r.Body\n", reqBodyVarName)
 
 			} else if paramType == "httper.Cookier" {
@@ -230,45 +374,347 @@ func New%v(embed %v, finalizer httper.Finalizer) *%v {
 
 			} else if (paramType == "http.ResponseWriter" && p == "w") || paramType == "*http.Request" && p == "r" {
 				//skip
+				takesWriter = takesWriter || p == "w"
 
 			} else if paramType == "httper.Sessionner" {
 				methodInvokation += fmt.Sprintf("var %v %v\n", p, paramType)
 				methodInvokation += fmt.Sprintf("%v = t.sessioner.Make(w, r)\n", p)
+				sessionVars = append(sessionVars, p)
+
+			} else if paramType == "context.Context" {
+				fileOut.AddImport("context", "")
+				methodInvokation += fmt.Sprintf("var %v %v\n", p, paramType)
+				methodInvokation += fmt.Sprintf("%v = r.Context()\n", p)
+
+			} else if isPrincipalParam(p, paramType, paramTypesInfo[i]) {
+				needsPrincipal = true
+				typeName := types.TypeString(paramTypesInfo[i], importQualifier(pkg, fileOut))
+				methodInvokation += fmt.Sprintf("var %v %v\n", p, typeName)
+				methodInvokation += fmt.Sprintf("if x, ok := authPrincipal.(%v); ok {\n%v = x\n", typeName, p)
+				methodInvokation += "} else if t.finalizer.HandleError(httper.Forbidden(\"invalid principal\", nil),w,r) {\nreturn\n}\n"
+
+			} else if len(getBindFields(paramTypesInfo[i])) > 0 {
+				methodInvokation += fmt.Sprintf("var %v %v\n", p, paramType)
+				methodInvokation += fmt.Sprintf("if bindErr := httper.BindData(httpData, &%v); bindErr != nil && t.finalizer.HandleError(bindErr,w,r) {\nreturn\n}\n", p)
+				readsData = true
 
 			} else if isConvetionnedParam(mode, p) {
 				prefix := getParamConvention(mode, p)
-				name := strings.ToLower(p[len(prefix):])
+				name := getParamName(prefix, p)
 
 				methodInvokation += fmt.Sprintf("var %v %v\n", p, paramType)
+				readsData = true
 
 				//handle prefixed data
-				expr := fmt.Sprintf("t.dataer.Make(w,r).Get(%q, %q)", prefix, name)
-				methodInvokation += convertedStr(p, expr, paramType)
+				if prefix == "file" {
+					fileOut.AddImport("mime/multipart", "")
+					files, err := fileParam(p, paramTypesInfo[i], prefix, name)
+					if err != nil {
+						return fmt.Errorf("%v.%v: %v", srcName, methodName, err)
+					}
+					methodInvokation += files
+
+				} else if elemType := sliceElem(paramTypesInfo[i]); elemType != nil && !isTextUnmarshaler(paramTypesInfo[i]) && !isBytesType(paramTypesInfo[i]) {
+					expr := fmt.Sprintf("httpData.GetAll(%q, %q)", prefix, name)
+					if imp := convImport(elemType); imp != "" && imp != pkg.Pkg.Path() {
+						fileOut.AddImport(imp, "")
+					}
+					elemTypeName := types.TypeString(elemType, importQualifier(pkg, fileOut))
+					conv, err := convertedStrs(p, expr, elemTypeName, elemType, prefix, name)
+					if err != nil {
+						return fmt.Errorf("%v.%v: %v", srcName, methodName, err)
+					}
+					methodInvokation += conv
+				} else {
+					expr := fmt.Sprintf("httpData.Get(%q, %q)", prefix, name)
+					if imp := convImport(paramTypesInfo[i]); imp != "" && imp != pkg.Pkg.Path() {
+						fileOut.AddImport(imp, "")
+					}
+					conv, err := convertedStr(p, expr, paramType, paramTypesInfo[i], prefix, name)
+					if err != nil {
+						return fmt.Errorf("%v.%v: %v", srcName, methodName, err)
+					}
+					methodInvokation += conv
+				}
 
 			} else {
 				methodInvokation += fmt.Sprintf("var %v %v\n", p, paramType)
 			}
 		}
 
+		if readsData {
+			methodInvokation = "httpData := t.dataer.Make(w, r)\n" + methodInvokation
+		}
+
+		checks, err := validateParams(mode, lParamNames, paramTypesInfo, validations)
+		if err != nil {
+			return fmt.Errorf("%v.%v: %v", srcName, methodName, err)
+		}
+		methodInvokation += checks
+
+		// authenticate before reading the request.
+		authenticate := ""
+		if needsPrincipal {
+			authenticate = "authPrincipal, authErr := httper.Authenticate(t.authenticator, r)\n"
+			authenticate += handleErr("authErr")
+		}
+		if len(roles) > 0 || len(permissions) > 0 {
+			authenticate += fmt.Sprintf("authzErr := httper.Authorize(t.authorizer, r, authPrincipal, %v, %v)\n",
+				stringSlice(roles), stringSlice(permissions))
+			authenticate += handleErr("authzErr")
+		}
+		methodInvokation = allowMethods + authenticate + methodInvokation
+
+		// the handler is wrapped by the global middlewares,
+		// then by the middlewares of the @middleware annotation.
+		middlewares := "t.middlewares"
+		if names := getAnnotationList(annotations, "middleware"); len(names) > 0 {
+			middlewares = fmt.Sprintf("t.methodMiddlewares[%q]", methodName)
+		}
+
+		// save the sessions before the response is written.
+		saveSessions := ""
+		for _, p := range sessionVars {
+			saveSessions += fmt.Sprintf("if saveErr := %v.Save(); saveErr != nil && t.finalizer.HandleError(saveErr,w,r) {\nreturn\n}\n", p)
+		}
+
 		// proceed to the method invokcation on embed
-		body := fmt.Sprintf(`
+		body := ""
+		resultTypesInfo := methodResultTypesInfo(pkg, m)
+		if len(resultTypesInfo) == 2 && isIOReaderType(resultTypesInfo[0]) && isErrorType(resultTypesInfo[1]) {
+			body = fmt.Sprintf(`
 		  res, err := t.embed.%v(%v)
 		  %v
-			t.finalizer.HandleSuccess(w, res)
-`, methodName, paramNames, handleErr("err"))
+		  %v
+			t.finalizer.HandleSuccess(w, r, res)
+`, methodName, paramNames, handleErr("err"), saveSessions)
+
+		} else {
+			retVars := []string{}
+			values := []string{}
+			for i, t := range resultTypesInfo {
+				if isErrorType(t) {
+					retVars = append(retVars, "err")
+				} else {
+					retVars = append(retVars, fmt.Sprintf("retVar%v", i))
+					values = append(values, fmt.Sprintf("retVar%v", i))
+				}
+			}
+			if len(retVars) > 0 {
+				body += fmt.Sprintf("%v := ", strings.Join(retVars, ", "))
+			}
+			body += fmt.Sprintf("t.embed.%v(%v)\n", methodName, paramNames)
+			if len(retVars) > len(values) {
+				body += handleErr("err")
+			}
+			body += saveSessions
+			if !takesWriter || len(values) > 0 {
+				body += fmt.Sprintf("t.finalizer.HandleSuccess(%v)\n", strings.Join(append([]string{"w", "r"}, values...), ", "))
+			}
+		}
 
 		fmt.Fprintf(dest, `// %v invoke %v.%v using the request body as a json payload.
 			%v
 func (t %v) %v(w http.ResponseWriter, r *http.Request) {
+	httper.Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
   %v
   %v
-}`, methodName, srcName, methodName, comment, dstStar, methodName, methodInvokation, body)
+	}), %v...).ServeHTTP(w, r)
+}`, methodName, srcName, methodName, comment, dstStar, methodName, methodInvokation, body, middlewares)
 		fmt.Fprintln(dest)
+
+		if mode == gorillaMode {
+			methods := ""
+			if len(route.Methods) > 0 {
+				methods = fmt.Sprintf("Methods: %#v, ", route.Methods)
+			}
+			routes += fmt.Sprintf("{Name: %q, Path: %q, %vHandler: t.%v},\n", route.Name, route.Path, methods, methodName)
+		}
+	}
+
+	if mode == gorillaMode {
+		fileOut.AddImport("github.com/gorilla/mux", "")
+
+		fmt.Fprintf(dest, `// Routes returns the routes of %v.
+func (t %v) Routes() httper.Routes {
+	return httper.Routes{
+		%v
+	}
+}
+`, destName, dstStar, routes)
+
+		fmt.Fprintf(dest, `// Bind the routes of %v to r.
+func (t %v) Bind(r *mux.Router) {
+	for _, route := range t.Routes() {
+		x := r.HandleFunc(route.Path, route.Handler).Name(route.Name)
+		if len(route.Methods) > 0 {
+			x.Methods(route.Methods...)
+		}
+	}
+}
+`, destName, dstStar)
 	}
 
 	return nil
 }
 
+// stringSlice returns the []string literal of l, nil when it is empty.
+func stringSlice(l []string) string {
+	if len(l) == 0 {
+		return "nil"
+	}
+	return fmt.Sprintf("[]string{%v}", quoteList(l))
+}
+
+func quoteList(l []string) string {
+	ret := []string{}
+	for _, s := range l {
+		ret = append(ret, fmt.Sprintf("%q", s))
+	}
+	return strings.Join(ret, ", ")
+}
+
+// getAnnotations returns the @key value lines of a comment.
+func getAnnotations(comment string) map[string]string {
+	ret := map[string]string{}
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "@") {
+			k := strings.SplitN(line[1:], " ", 2)
+			if len(k) > 1 {
+				ret[k[0]] = strings.TrimSpace(k[1])
+			} else {
+				ret[k[0]] = ""
+			}
+		}
+	}
+	return ret
+}
+
+// getAnnotationList returns the comma separated values of the @key annotation,
+// "@middleware auth,audit".
+func getAnnotationList(annotations map[string]string, key string) []string {
+	ret := []string{}
+	for _, value := range strings.Split(annotations[key], ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			ret = append(ret, value)
+		}
+	}
+	return ret
+}
+
+// getAnnotationValues returns the values of each @key line of comment.
+func getAnnotationValues(comment, key string) []string {
+	ret := []string{}
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "@"+key+" ") {
+			ret = append(ret, strings.TrimSpace(line[len(key)+1:]))
+		}
+	}
+	return ret
+}
+
+var verbMethods = []struct {
+	Prefix string
+	Method string
+}{
+	{"Get", "GET"},
+	{"List", "GET"},
+	{"Find", "GET"},
+	{"Create", "POST"},
+	{"Add", "POST"},
+	{"Post", "POST"},
+	{"Update", "PUT"},
+	{"Put", "PUT"},
+	{"Patch", "PATCH"},
+	{"Delete", "DELETE"},
+	{"Remove", "DELETE"},
+}
+
+// getRoute derives the route of a method from its name, its parameters,
+// the url / route fields of its struct parameters, and its @route / @method / @name annotations.
+// It fails on an empty @method, or an unknown http method.
+func getRoute(mode, methodName, paramNames string, paramTypesInfo []types.Type, annotations map[string]string) (httper.Route, error) {
+	route := httper.Route{Name: methodName}
+
+	if name, ok := annotations["name"]; ok && name != "" {
+		route.Name = name
+	}
+
+	if path, ok := annotations["route"]; ok {
+		route.Path = path
+	} else {
+		route.Path = "/" + strings.ToLower(methodName)
+		for i, p := range strings.Split(paramNames, ",") {
+			p = strings.TrimSpace(p)
+			if p == "" || p == reqBodyVarName {
+				continue
+			}
+			prefix := getVarPrefix(mode, p)
+			if prefix == "url" || prefix == "route" {
+				route.Path += fmt.Sprintf("/{%v}", strings.ToLower(p[len(prefix):]))
+				continue
+			}
+			if i >= len(paramTypesInfo) {
+				continue
+			}
+			for _, f := range getBindFields(paramTypesInfo[i]) {
+				if (f.Prefix == "url" || f.Prefix == "route") && hasDataProvider(mode, f.Prefix) &&
+					!strings.Contains(route.Path, "{"+f.Name+"}") {
+					route.Path += fmt.Sprintf("/{%v}", f.Name)
+				}
+			}
+		}
+	}
+
+	if methods, ok := annotations["method"]; ok {
+		for _, m := range strings.Split(methods, ",") {
+			if m = strings.TrimSpace(m); m == "" {
+				continue
+			}
+			if !isHTTPMethod(strings.ToUpper(m)) {
+				return route, fmt.Errorf("unknown @method %q", m)
+			}
+			route.Methods = append(route.Methods, strings.ToUpper(m))
+		}
+		if len(route.Methods) == 0 {
+			return route, fmt.Errorf("empty @method, want a comma separated list of http methods")
+		}
+	} else {
+		for _, v := range verbMethods {
+			if strings.HasPrefix(methodName, v.Prefix) {
+				route.Methods = append(route.Methods, v.Method)
+				break
+			}
+		}
+	}
+
+	return route, nil
+}
+
+// isHTTPMethod returns true when m is a method of net/http.
+func isHTTPMethod(m string) bool {
+	switch m {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// getRouteMethods returns the methods of a route,
+// when it does not define any, it is POST for a request having a body, GET otherwise.
+func getRouteMethods(route httper.Route, hasBody bool) []string {
+	if len(route.Methods) > 0 {
+		return route.Methods
+	}
+	if hasBody {
+		return []string{"POST"}
+	}
+	return []string{"GET"}
+}
+
 func makeCommentLines(s string) string {
 	s = strings.TrimSpace(s)
 	comment := ""
@@ -303,6 +749,9 @@ func isUsingConvetionnedParams(mode, params string) bool {
 
 			} else if varType == "httper.Sessionner" {
 				return true
+
+			} else if varType == "context.Context" {
+				return true
 			}
 		}
 		varName := strings.TrimSpace(k[0])
@@ -313,6 +762,41 @@ func isUsingConvetionnedParams(mode, params string) bool {
 	return false
 }
 
+// principalName is the name, or name prefix, of the principal parameters.
+var principalName = "principal"
+
+// principalType is the type of the principal parameters,
+// when it is set, the parameters are matched by their type only.
+var principalType = ""
+
+// isPrincipalParam returns true when the parameter varName of type varType
+// receives the authenticated principal. Without a principalType, it is matched
+// by its name, and its type t must be a struct or an interface,
+// principalID int is not a principal.
+func isPrincipalParam(varName, varType string, t types.Type) bool {
+	if principalType != "" {
+		return varType == principalType
+	}
+	if principalName == "" || !strings.HasPrefix(varName, principalName) {
+		return false
+	}
+	rest := varName[len(principalName):]
+	if rest != "" && !unicode.IsUpper(rune(rest[0])) {
+		return false
+	}
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if t == nil {
+		return false
+	}
+	switch t.Underlying().(type) {
+	case *types.Struct, *types.Interface:
+		return true
+	}
+	return false
+}
+
 func isConvetionnedParam(mode, varName string) bool {
 	if varName == reqBodyVarName {
 		return true
@@ -320,6 +804,28 @@ func isConvetionnedParam(mode, varName string) bool {
 	return getVarPrefix(mode, varName) != ""
 }
 
+// getParamName returns the name of the data of the prefixed varName,
+// headerXRequestID reads the header X-Request-Id, getID reads the data id.
+func getParamName(prefix, varName string) string {
+	name := varName[len(prefix):]
+	if prefix != "header" {
+		return strings.ToLower(name)
+	}
+	words := []string{}
+	start := 0
+	for i := 1; i < len(name); i++ {
+		prevUpper := unicode.IsUpper(rune(name[i-1]))
+		upper := unicode.IsUpper(rune(name[i]))
+		nextLower := i+1 < len(name) && unicode.IsLower(rune(name[i+1]))
+		if upper && (!prevUpper || nextLower) {
+			words = append(words, name[start:i])
+			start = i
+		}
+	}
+	words = append(words, name[start:])
+	return http.CanonicalHeaderKey(strings.Join(words, "-"))
+}
+
 func getParamConvention(mode, varName string) string {
 	if varName == reqBodyVarName {
 		return reqBodyVarName
@@ -330,7 +836,7 @@ func getParamConvention(mode, varName string) string {
 func getSessionProviderFactory(mode string) httper.SessionProvider {
 	var factory httper.SessionProvider
 	if mode == stdMode {
-		factory = &httper.VoidSessionProvider{}
+		factory = &httper.StoreSessionProvider{}
 	} else if mode == gorillaMode {
 		factory = &httper.GorillaSessionProvider{}
 	}
@@ -351,11 +857,25 @@ func getDataProvider(mode string) *httper.DataProviderFacade {
 	return getDataProviderFactory(mode).MakeEmpty().(*httper.DataProviderFacade)
 }
 
+// hasDataProvider tells if the data of prefix are provided in mode.
+func hasDataProvider(mode, prefix string) bool {
+	for _, p := range getDataProvider(mode).Providers {
+		if p.GetName() == prefix {
+			return true
+		}
+	}
+	return false
+}
+
 func getVarPrefix(mode, varName string) string {
 	ret := ""
 	provider := getDataProvider(mode)
 	for _, p := range provider.Providers {
 		prefix := p.GetName()
+		if len(varName) <= len(prefix) {
+			// a name without a suffix, such as header, is not a convention.
+			continue
+		}
 		if strings.HasPrefix(varName, strings.ToLower(prefix)) {
 			f := string(varName[len(prefix):][0])
 			if f == strings.ToUpper(f) {
@@ -373,25 +893,477 @@ func getVarPrefix(mode, varName string) string {
 	return ret
 }
 
-func convertedStr(toVarName, expr string, toType string) string {
-	if toType == "int" {
-		expr = convStrToInt(expr, toVarName)
-	} else {
-		expr = fmt.Sprintf("%v := %v\n", toVarName, expr)
+// wrappedMethods returns the exported methods of srcName wrapped by its httper.
+func wrappedMethods(pkg *loader.PackageInfo, srcName string) []*ast.FuncDecl {
+	ret := []*ast.FuncDecl{}
+	for _, m := range astutil.FindMethods(pkg)[astutil.GetUnpointedType(srcName)] {
+		methodName := astutil.MethodName(m)
+		if astutil.IsExported(methodName) == false {
+			continue
+		}
+		if methodName == "UnmarshalJSON" || methodName == "MarshalJSON" {
+			continue
+		}
+		ret = append(ret, m)
 	}
-	return expr
+	return ret
 }
-func convStrToInt(fromVarName, toVarName string) string {
-	methodInvokation := fmt.Sprintf("temp%v, err := strconv.Atoi(%v)\n", toVarName, fromVarName)
-	methodInvokation += handleErr("err")
-	methodInvokation += fmt.Sprintf("%v = temp%v\n", toVarName, toVarName)
-	return methodInvokation
+
+// methodParamTypesInfo returns the type information of each parameter of m.
+func methodParamTypesInfo(pkg *loader.PackageInfo, m *ast.FuncDecl) []types.Type {
+	ret := []types.Type{}
+	for _, field := range m.Type.Params.List {
+		t := pkg.TypeOf(field.Type)
+		for range field.Names {
+			ret = append(ret, t)
+		}
+	}
+	return ret
+}
+
+// importQualifier qualifies the types of the packages other than pkg,
+// it adds their import to fileOut.
+func importQualifier(pkg *loader.PackageInfo, fileOut *utils.FileOut) types.Qualifier {
+	return func(p *types.Package) string {
+		if p == pkg.Pkg {
+			return ""
+		}
+		fileOut.AddImport(p.Path(), "")
+		return p.Name()
+	}
+}
+
+// sliceElem returns the element type of a slice, or nil.
+func sliceElem(t types.Type) types.Type {
+	if t == nil {
+		return nil
+	}
+	if slice, ok := t.Underlying().(*types.Slice); ok {
+		return slice.Elem()
+	}
+	return nil
+}
+
+// isBytesType is true when t is a []byte, it is converted from a single string.
+func isBytesType(t types.Type) bool {
+	b, ok := sliceElem(t).(*types.Basic)
+	return ok && b.Kind() == types.Byte
+}
+
+// methodResultTypesInfo returns the type information of each result of m.
+func methodResultTypesInfo(pkg *loader.PackageInfo, m *ast.FuncDecl) []types.Type {
+	ret := []types.Type{}
+	if m.Type.Results == nil {
+		return ret
+	}
+	for _, field := range m.Type.Results.List {
+		t := pkg.TypeOf(field.Type)
+		if len(field.Names) == 0 {
+			ret = append(ret, t)
+		}
+		for range field.Names {
+			ret = append(ret, t)
+		}
+	}
+	return ret
+}
+
+// strConverter describes how to convert a string to a type.
+type strConverter struct {
+	// Func is the format of the conversion call, it receives the string expression.
+	Func string
+	// Type is the type returned by Func.
+	Type string
+	// Import is the package required by Func.
+	Import string
+	// Unmarshal is true when the type implements encoding.TextUnmarshaler.
+	Unmarshal bool
+}
+
+// textUnmarshaler is the encoding.TextUnmarshaler interface.
+var textUnmarshaler = types.NewInterfaceType([]*types.Func{
+	types.NewFunc(0, nil, "UnmarshalText", types.NewSignatureType(nil, nil, nil,
+		types.NewTuple(types.NewVar(0, nil, "text", types.NewSlice(types.Typ[types.Byte]))),
+		types.NewTuple(types.NewVar(0, nil, "", types.Universe.Lookup("error").Type())),
+		false)),
+}, nil).Complete()
+
+// ioReader is the io.Reader interface.
+var ioReader = types.NewInterfaceType([]*types.Func{
+	types.NewFunc(0, nil, "Read", types.NewSignatureType(nil, nil, nil,
+		types.NewTuple(types.NewVar(0, nil, "p", types.NewSlice(types.Typ[types.Byte]))),
+		types.NewTuple(
+			types.NewVar(0, nil, "n", types.Typ[types.Int]),
+			types.NewVar(0, nil, "err", types.Universe.Lookup("error").Type()),
+		),
+		false)),
+}, nil).Complete()
+
+func implementsReader(t types.Type) bool {
+	return t != nil && types.Implements(t, ioReader)
+}
+
+// isBodyReaderType is true when an http request body can be assigned to t.
+func isBodyReaderType(t types.Type) bool {
+	return t != nil && types.IsInterface(t) && implementsReader(t)
+}
+
+// validateParams returns the code to check the parameters against
+// the @validate annotations, "@validate getLimit min=1,max=100",
+// and the struct parameters against their httper.ValidateTag.
+// The violations are handled as a single error.
+// It fails on the annotations of unknown parameters.
+func validateParams(mode string, paramNames []string, paramTypes []types.Type, validations []string) (string, error) {
+	checks := ""
+	for _, v := range validations {
+		k := strings.SplitN(v, " ", 2)
+		if len(k) < 2 {
+			return "", fmt.Errorf("invalid @validate %q, want @validate <parameter> <rules>", v)
+		}
+		p, rules := strings.TrimSpace(k[0]), strings.TrimSpace(k[1])
+		if err := checkRuleNames(rules); err != nil {
+			return "", fmt.Errorf("invalid @validate %q, %v", v, err)
+		}
+		found := false
+		for _, paramName := range paramNames {
+			if strings.TrimSpace(paramName) != p {
+				continue
+			}
+			found = true
+			field := p
+			if prefix := getVarPrefix(mode, p); prefix != "" {
+				field = getParamName(prefix, p)
+			}
+			checks += fmt.Sprintf("violations = violations.Check(%q, %v, %q)\n", field, p, rules)
+		}
+		if !found {
+			return "", fmt.Errorf("@validate of an unknown parameter %q", p)
+		}
+	}
+	for i, p := range paramNames {
+		p = strings.TrimSpace(p)
+		if i < len(paramTypes) && hasValidateTags(paramTypes[i], map[types.Type]bool{}) {
+			checks += fmt.Sprintf("violations = violations.Struct(\"\", %v)\n", p)
+		}
+	}
+	if checks == "" {
+		return "", nil
+	}
+	checks += "if verr := violations.Err(); verr != nil && t.finalizer.HandleError(verr,w,r) {\nreturn\n}\n"
+	return "var violations httper.Violations\n" + checks, nil
+}
+
+// checkRuleNames returns an error for the unknown rules,
+// the pattern rule ends the list as it can contain commas.
+func checkRuleNames(rules string) error {
+	for _, rule := range strings.Split(rules, ",") {
+		name := strings.TrimSpace(strings.SplitN(rule, "=", 2)[0])
+		switch name {
+		case "", "required", "min", "max", "oneof":
+		case "pattern":
+			return nil
+		default:
+			return fmt.Errorf("unknown rule %q, want required, min, max, oneof or pattern", name)
+		}
+	}
+	return nil
+}
+
+// hasValidateTags returns true when the struct t,
+// or one of its struct fields, has an httper.ValidateTag.
+func hasValidateTags(t types.Type, seen map[types.Type]bool) bool {
+	if t == nil || seen[t] {
+		return false
+	}
+	seen[t] = true
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	s, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+	for i := 0; i < s.NumFields(); i++ {
+		if reflect.StructTag(s.Tag(i)).Get(httper.ValidateTag) != "" {
+			return true
+		}
+		if s.Field(i).Exported() && hasValidateTags(s.Field(i).Type(), seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// bindField is a field of a struct bound with httper.BindData.
+type bindField struct {
+	Path   string
+	Prefix string
+	Name   string
+	Type   types.Type
+	// Rules of its httper.ValidateTag.
+	Rules string
+}
+
+// getBindFields returns the fields of the struct t having an httper.BindTag,
+// the fields of the embedded structs are included.
+func getBindFields(t types.Type) []bindField {
+	if t == nil {
+		return nil
+	}
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	s, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+	ret := []bindField{}
+	for i := 0; i < s.NumFields(); i++ {
+		field := s.Field(i)
+		tag := reflect.StructTag(s.Tag(i)).Get(httper.BindTag)
+		if tag == "" && field.Anonymous() {
+			for _, f := range getBindFields(field.Type()) {
+				f.Path = field.Name() + "." + f.Path
+				ret = append(ret, f)
+			}
+			continue
+		}
+		if tag == "" || tag == "-" || !field.Exported() {
+			continue
+		}
+		prefix, name := httper.ParseBindTag(tag, field.Name())
+		rules := reflect.StructTag(s.Tag(i)).Get(httper.ValidateTag)
+		ret = append(ret, bindField{Path: field.Name(), Prefix: prefix, Name: name, Type: field.Type(), Rules: rules})
+	}
+	return ret
+}
+
+func isFileHeaderType(t types.Type) bool {
+	ptr, ok := t.(*types.Pointer)
+	if !ok {
+		return false
+	}
+	named, ok := ptr.Elem().(*types.Named)
+	return ok && named.Obj().Pkg() != nil &&
+		named.Obj().Pkg().Path() == "mime/multipart" && named.Obj().Name() == "FileHeader"
+}
+
+func isStructType(t types.Type) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	_, ok := t.Underlying().(*types.Struct)
+	return ok
+}
+
+func isIOReaderType(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil &&
+		named.Obj().Pkg().Path() == "io" && named.Obj().Name() == "Reader"
+}
+
+func isErrorType(t types.Type) bool {
+	return t != nil && types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+func isTextUnmarshaler(t types.Type) bool {
+	if _, ok := t.(*types.Pointer); ok {
+		return types.Implements(t, textUnmarshaler)
+	}
+	return types.Implements(types.NewPointer(t), textUnmarshaler)
+}
+
+func typePkgPath(t types.Type) string {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := t.(*types.Named); ok && named.Obj().Pkg() != nil {
+		return named.Obj().Pkg().Path()
+	}
+	return ""
+}
+
+// durationTypes are the types defined from time.Duration, such as type Timeout time.Duration.
+var durationTypes = map[*types.TypeName]bool{}
+
+// findDurationTypes registers the types of prog defined from time.Duration,
+// their definitions are read as the types only know their underlying int64.
+func findDurationTypes(prog *loader.Program) {
+	for found := true; found; {
+		found = false
+		for _, pkg := range prog.AllPackages {
+			for _, file := range pkg.Files {
+				for _, decl := range file.Decls {
+					gen, ok := decl.(*ast.GenDecl)
+					if !ok || gen.Tok != token.TYPE {
+						continue
+					}
+					for _, spec := range gen.Specs {
+						typeSpec := spec.(*ast.TypeSpec)
+						obj, ok := pkg.Defs[typeSpec.Name].(*types.TypeName)
+						if ok && !durationTypes[obj] && isDurationType(pkg.TypeOf(typeSpec.Type)) {
+							durationTypes[obj] = true
+							found = true
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// isDurationType returns true when t is time.Duration, or a type defined from it.
+func isDurationType(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return durationTypes[obj] || obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Duration"
+}
+
+func getStrConverter(t types.Type) *strConverter {
+	if t == nil {
+		return nil
+	}
+	if named, ok := t.(*types.Named); ok && named.Obj().Pkg() != nil {
+		if named.Obj().Pkg().Path() == "time" && named.Obj().Name() == "Time" {
+			return &strConverter{Func: "time.Parse(time.RFC3339, %v)", Type: "time.Time", Import: "time"}
+		}
+	}
+	if isTextUnmarshaler(t) {
+		return &strConverter{Import: typePkgPath(t), Unmarshal: true}
+	}
+	if isDurationType(t) {
+		return &strConverter{Func: "time.ParseDuration(%v)", Type: "time.Duration", Import: "time"}
+	}
+	if isBytesType(t) {
+		return &strConverter{Func: "%v", Type: "string"}
+	}
+	basic, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return nil
+	}
+	switch basic.Kind() {
+	case types.String:
+		return &strConverter{Func: "%v", Type: "string"}
+	case types.Bool:
+		return &strConverter{Func: "strconv.ParseBool(%v)", Type: "bool", Import: "strconv"}
+	case types.Int:
+		return &strConverter{Func: "strconv.Atoi(%v)", Type: "int", Import: "strconv"}
+	case types.Int8:
+		return &strConverter{Func: "strconv.ParseInt(%v, 10, 8)", Type: "int64", Import: "strconv"}
+	case types.Int16:
+		return &strConverter{Func: "strconv.ParseInt(%v, 10, 16)", Type: "int64", Import: "strconv"}
+	case types.Int32:
+		return &strConverter{Func: "strconv.ParseInt(%v, 10, 32)", Type: "int64", Import: "strconv"}
+	case types.Int64:
+		return &strConverter{Func: "strconv.ParseInt(%v, 10, 64)", Type: "int64", Import: "strconv"}
+	case types.Uint:
+		return &strConverter{Func: "strconv.ParseUint(%v, 10, 0)", Type: "uint64", Import: "strconv"}
+	case types.Uint8:
+		return &strConverter{Func: "strconv.ParseUint(%v, 10, 8)", Type: "uint64", Import: "strconv"}
+	case types.Uint16:
+		return &strConverter{Func: "strconv.ParseUint(%v, 10, 16)", Type: "uint64", Import: "strconv"}
+	case types.Uint32:
+		return &strConverter{Func: "strconv.ParseUint(%v, 10, 32)", Type: "uint64", Import: "strconv"}
+	case types.Uint64:
+		return &strConverter{Func: "strconv.ParseUint(%v, 10, 64)", Type: "uint64", Import: "strconv"}
+	case types.Float32:
+		return &strConverter{Func: "strconv.ParseFloat(%v, 32)", Type: "float64", Import: "strconv"}
+	case types.Float64:
+		return &strConverter{Func: "strconv.ParseFloat(%v, 64)", Type: "float64", Import: "strconv"}
+	}
+	return nil
+}
+
+func convImport(t types.Type) string {
+	if conv := getStrConverter(t); conv != nil {
+		return conv.Import
+	}
+	return ""
+}
+
+func convertedStr(toVarName, expr string, toTypeName string, toType types.Type, prefix, name string) (string, error) {
+	conv := getStrConverter(toType)
+	if conv == nil {
+		return "", fmt.Errorf("parameter %v of type %v can not be converted from a string", toVarName, toTypeName)
+	}
+	methodInvokation := fmt.Sprintf("temp%v := %v\n", toVarName, expr)
+	methodInvokation += fmt.Sprintf("if temp%v != \"\" {\n", toVarName)
+	stmt, value := convStrTo(conv, "temp"+toVarName, toVarName, toTypeName, prefix, name)
+	methodInvokation += stmt
+	methodInvokation += fmt.Sprintf("%v = %v\n", toVarName, value)
+	methodInvokation += "}\n"
+	return methodInvokation, nil
+}
+
+// fileParam reads the multipart files of name into toVarName,
+// it is a *multipart.FileHeader, a []*multipart.FileHeader or an io.Reader of the first file.
+func fileParam(toVarName string, toType types.Type, prefix, name string) (string, error) {
+	methodInvokation := fmt.Sprintf("if files, ok := httpData.GetAny(%q, %q).([]*multipart.FileHeader); ok && len(files) > 0 {\n", prefix, name)
+	switch {
+	case isFileHeaderType(toType):
+		methodInvokation += fmt.Sprintf("%v = files[0]\n", toVarName)
+	case isFileHeaderType(sliceElem(toType)):
+		methodInvokation += fmt.Sprintf("%v = files\n", toVarName)
+	case isIOReaderType(toType):
+		methodInvokation += "f, openErr := files[0].Open()\n"
+		methodInvokation += handleParamErr("openErr", prefix, name)
+		methodInvokation += "defer f.Close()\n"
+		methodInvokation += fmt.Sprintf("%v = f\n", toVarName)
+	default:
+		return "", fmt.Errorf("parameter %v of type %v can not be read from a file", toVarName, toType)
+	}
+	methodInvokation += "}\n"
+	return methodInvokation, nil
+}
+func convertedStrs(toVarName, expr string, toElemTypeName string, toElemType types.Type, prefix, name string) (string, error) {
+	conv := getStrConverter(toElemType)
+	if conv == nil {
+		return "", fmt.Errorf("parameter %v of type []%v can not be converted from strings", toVarName, toElemTypeName)
+	}
+	methodInvokation := fmt.Sprintf("for _, temp%v := range %v {\n", toVarName, expr)
+	stmt, value := convStrTo(conv, "temp"+toVarName, toVarName, toElemTypeName, prefix, name)
+	methodInvokation += stmt
+	methodInvokation += fmt.Sprintf("%v = append(%v, %v)\n", toVarName, toVarName, value)
+	methodInvokation += "}\n"
+	return methodInvokation, nil
+}
+func convStrTo(conv *strConverter, fromVarName, toVarName, toTypeName, prefix, name string) (string, string) {
+	if conv.Unmarshal {
+		methodInvokation := fmt.Sprintf("var conv%v %v\n", toVarName, toTypeName)
+		if strings.HasPrefix(toTypeName, "*") {
+			methodInvokation = fmt.Sprintf("conv%v := new(%v)\n", toVarName, astutil.GetUnpointedType(toTypeName))
+		}
+		methodInvokation += fmt.Sprintf("err := conv%v.UnmarshalText([]byte(%v))\n", toVarName, fromVarName)
+		methodInvokation += handleParamErr("err", prefix, name)
+		return methodInvokation, "conv" + toVarName
+	}
+	if conv.Type == "string" {
+		if toTypeName == "string" {
+			return "", fromVarName
+		}
+		return "", fmt.Sprintf("%v(%v)", toTypeName, fromVarName)
+	}
+	methodInvokation := fmt.Sprintf("conv%v, err := %v\n", toVarName, fmt.Sprintf(conv.Func, fromVarName))
+	methodInvokation += handleParamErr("err", prefix, name)
+	if toTypeName == conv.Type {
+		return methodInvokation, "conv" + toVarName
+	}
+	return methodInvokation, fmt.Sprintf("%v(conv%v)", toTypeName, toVarName)
 }
 func handleErr(errVarName string) string {
-	methodInvokation := fmt.Sprintf(`if err != nil && t.finalizer.HandleError(%v,w,r) {
+	return handleErrWith(errVarName, errVarName)
+}
+func handleParamErr(errVarName, prefix, name string) string {
+	return handleErrWith(errVarName, fmt.Sprintf("httper.ParamError(%q, %q, %v)", prefix, name, errVarName))
+}
+func handleErrWith(errVarName, errExpr string) string {
+	methodInvokation := fmt.Sprintf(`if %v != nil && t.finalizer.HandleError(%v,w,r) {
 return
 }
-`, errVarName)
+`, errVarName, errExpr)
 	return methodInvokation
 }
 func paramType(params string) string {
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"github.com/mh-cbon/astutil"
+	"github.com/mh-cbon/httper/utils"
+	"golang.org/x/tools/go/loader"
+)
+
+// embedInterface types the embedded value of the httpers
+// with the interface of the methods they wrap,
+// its recording mock is generated in a test file.
+var embedInterface = false
+
+// embedInterfaceName returns the name of the interface of the methods wrapped by destName.
+func embedInterfaceName(destName string) string {
+	return astutil.GetUnpointedType(destName) + "Embed"
+}
+
+// embedMockName returns the name of the recording mock of the interface of destName.
+func embedMockName(destName string) string {
+	return embedInterfaceName(destName) + "Mock"
+}
+
+// embedCallName returns the name of the calls recorded by the mock of destName.
+func embedCallName(destName string) string {
+	return embedInterfaceName(destName) + "Call"
+}
+
+// mockSignature is a method signature with named parameters.
+type mockSignature struct {
+	Params   []string
+	Types    []string
+	Results  []string
+	Variadic bool
+}
+
+// getMockSignature returns the signature of m, its parameters
+// are renamed argN when they are unnamed or blank.
+func getMockSignature(pkg *loader.PackageInfo, m *ast.FuncDecl, qualifier types.Qualifier) mockSignature {
+	ret := mockSignature{}
+	fn, ok := pkg.Defs[m.Name].(*types.Func)
+	if !ok {
+		return ret
+	}
+	sig := fn.Type().(*types.Signature)
+	ret.Variadic = sig.Variadic()
+	for i := 0; i < sig.Params().Len(); i++ {
+		p := sig.Params().At(i)
+		name := p.Name()
+		if name == "" || name == "_" {
+			name = fmt.Sprintf("arg%v", i)
+		}
+		typeName := types.TypeString(p.Type(), qualifier)
+		if ret.Variadic && i == sig.Params().Len()-1 {
+			typeName = "..." + types.TypeString(p.Type().(*types.Slice).Elem(), qualifier)
+		}
+		ret.Params = append(ret.Params, name)
+		ret.Types = append(ret.Types, typeName)
+	}
+	for i := 0; i < sig.Results().Len(); i++ {
+		ret.Results = append(ret.Results, types.TypeString(sig.Results().At(i).Type(), qualifier))
+	}
+	return ret
+}
+
+// String returns the parameters and the results of the signature.
+func (s mockSignature) String() string {
+	params := []string{}
+	for i, p := range s.Params {
+		params = append(params, p+" "+s.Types[i])
+	}
+	ret := "(" + strings.Join(params, ", ") + ")"
+	if len(s.Results) == 1 {
+		ret += " " + s.Results[0]
+	} else if len(s.Results) > 1 {
+		ret += " (" + strings.Join(s.Results, ", ") + ")"
+	}
+	return ret
+}
+
+// Args returns the arguments to invoke a method of the signature.
+func (s mockSignature) Args() string {
+	ret := strings.Join(s.Params, ", ")
+	if s.Variadic {
+		ret += "..."
+	}
+	return ret
+}
+
+// processInterface generates the interface of the methods wrapped by the httper of todo.
+func processInterface(todo utils.TransformArg, fileOut *utils.FileOut) error {
+
+	dest := &fileOut.Body
+	srcName := todo.FromTypeName
+	destName := todo.ToTypeName
+
+	prog := astutil.GetProgramFast(todo.FromPkgPath)
+	pkg := prog.Package(todo.FromPkgPath)
+	qualifier := importQualifier(pkg, fileOut)
+
+	ifaceName := embedInterfaceName(destName)
+
+	var iface bytes.Buffer
+	for _, m := range wrappedMethods(pkg, srcName) {
+		fmt.Fprintf(&iface, "%v%v\n", astutil.MethodName(m), getMockSignature(pkg, m, qualifier))
+	}
+
+	fmt.Fprintf(dest, `
+// %v is the interface of the methods of %v wrapped by %v.
+type %v interface {
+%v}
+`, ifaceName, srcName, destName, ifaceName, iface.String())
+
+	return nil
+}
+
+// processMock generates the recording mock of the interface
+// of the methods wrapped by the httper of todo.
+func processMock(todo utils.TransformArg, fileOut *utils.FileOut) error {
+
+	dest := &fileOut.Body
+	srcName := todo.FromTypeName
+	destName := todo.ToTypeName
+
+	prog := astutil.GetProgramFast(todo.FromPkgPath)
+	pkg := prog.Package(todo.FromPkgPath)
+	qualifier := importQualifier(pkg, fileOut)
+
+	ifaceName := embedInterfaceName(destName)
+	mockName := embedMockName(destName)
+	callName := embedCallName(destName)
+	methods := wrappedMethods(pkg, srcName)
+
+	fileOut.AddImport("sync", "")
+
+	var mockFields bytes.Buffer
+	var mockMethods bytes.Buffer
+	for _, m := range methods {
+		methodName := astutil.MethodName(m)
+		sig := getMockSignature(pkg, m, qualifier)
+		fmt.Fprintf(&mockFields, "%vFunc func%v\n", methodName, sig)
+
+		invoke := fmt.Sprintf("mock.%vFunc(%v)", methodName, sig.Args())
+		body := fmt.Sprintf("if mock.%vFunc != nil {\n%v\n}\n", methodName, invoke)
+		if len(sig.Results) > 0 {
+			body = fmt.Sprintf("if mock.%vFunc != nil {\nreturn %v\n}\n", methodName, invoke)
+			results := []string{}
+			for i, r := range sig.Results {
+				body += fmt.Sprintf("var ret%v %v\n", i, r)
+				results = append(results, fmt.Sprintf("ret%v", i))
+			}
+			body += "return " + strings.Join(results, ", ") + "\n"
+		}
+		args := append([]string{fmt.Sprintf("%q", methodName)}, sig.Params...)
+		fmt.Fprintf(&mockMethods, `
+// %v records its call, then invokes %vFunc when it is set.
+func (mock *%v) %v%v {
+	mock.record(%v)
+	%v}
+`, methodName, methodName, mockName, methodName, sig, strings.Join(args, ", "), body)
+	}
+
+	fmt.Fprintf(dest, `
+// %v is a call recorded by a %v.
+type %v struct {
+	Method string
+	Args   []interface{}
+}
+
+// %v is a recording mock of %v,
+// its methods invoke the func of the same name when it is set,
+// they return zero values otherwise.
+type %v struct {
+%v
+	mu    sync.Mutex
+	calls []%v
+}
+
+var _ %v = &%v{}
+
+func (mock *%v) record(method string, args ...interface{}) {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	mock.calls = append(mock.calls, %v{Method: method, Args: args})
+}
+
+// Calls returns the recorded calls.
+func (mock *%v) Calls() []%v {
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	return append([]%v{}, mock.calls...)
+}
+%v`, callName, mockName, callName,
+		mockName, ifaceName, mockName, mockFields.String(), callName,
+		ifaceName, mockName,
+		mockName, callName,
+		mockName, callName, callName,
+		mockMethods.String())
+
+	return nil
+}
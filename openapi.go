@@ -0,0 +1,383 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"io/ioutil"
+	"reflect"
+	"strings"
+
+	"github.com/mh-cbon/astutil"
+	"github.com/mh-cbon/httper/utils"
+)
+
+// openAPIDoc is an OpenAPI 3 document.
+type openAPIDoc struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       openAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components openAPIComponents                       `json:"components,omitempty"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*openAPISchema `json:"schemas,omitempty"`
+}
+
+type openAPIOperation struct {
+	OperationID string                      `json:"operationId,omitempty"`
+	Summary     string                      `json:"summary,omitempty"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Parameters  []*openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required,omitempty"`
+	Explode  *bool          `json:"explode,omitempty"`
+	Schema   *openAPISchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                         `json:"required,omitempty"`
+	Content  map[string]*openAPIMediaType `json:"content"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPIResponse struct {
+	Description string `json:"description"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+}
+
+func newOpenAPIDoc() *openAPIDoc {
+	return &openAPIDoc{
+		OpenAPI: "3.0.0",
+		Info:    openAPIInfo{Version: "1.0.0"},
+		Paths:   map[string]map[string]*openAPIOperation{},
+		Components: openAPIComponents{
+			Schemas: map[string]*openAPISchema{},
+		},
+	}
+}
+
+// Write the document as json to the file o.
+func (d *openAPIDoc) Write(o string) error {
+	b, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(o, b, 0644)
+}
+
+// processOpenAPI describes the http api of todo into doc.
+func processOpenAPI(mode string, todo utils.TransformArg, doc *openAPIDoc) error {
+
+	srcName := todo.FromTypeName
+	destName := todo.ToTypeName
+
+	prog := astutil.GetProgramFast(todo.FromPkgPath)
+	pkg := prog.Package(todo.FromPkgPath)
+	foundMethods := astutil.FindMethods(pkg)
+	findDurationTypes(prog)
+
+	srcConcrete := astutil.GetUnpointedType(srcName)
+	destConcrete := astutil.GetUnpointedType(destName)
+
+	if doc.Info.Title == "" {
+		doc.Info.Title = destConcrete
+	} else {
+		doc.Info.Title += ", " + destConcrete
+	}
+
+	for _, m := range foundMethods[srcConcrete] {
+		methodName := astutil.MethodName(m)
+		paramNames := astutil.MethodParamNames(m)
+		paramTypes := astutil.MethodParamTypes(m)
+		paramTypesInfo := methodParamTypesInfo(pkg, m)
+
+		// ensure it is desired to facade this method.
+		if astutil.IsExported(methodName) == false {
+			continue
+		}
+		if methodName == "UnmarshalJSON" || methodName == "MarshalJSON" {
+			continue
+		}
+
+		comment := astutil.GetComment(prog, m.Pos())
+		annotations := getAnnotations(comment)
+		route, err := getRoute(mode, methodName, paramNames, paramTypesInfo, annotations)
+		if err != nil {
+			return fmt.Errorf("%v.%v: %v", srcName, methodName, err)
+		}
+
+		op := &openAPIOperation{
+			OperationID: route.Name,
+			Summary:     strings.TrimSpace(strings.Split(strings.TrimSpace(comment), "\n")[0]),
+			Tags:        []string{destConcrete},
+			Responses: map[string]*openAPIResponse{
+				"200":     {Description: "OK"},
+				"default": {Description: "Error"},
+			},
+		}
+		if strings.HasPrefix(op.Summary, "@") {
+			op.Summary = ""
+		}
+		if len(getAnnotationList(annotations, "roles")) > 0 || len(getAnnotationList(annotations, "permission")) > 0 {
+			op.Responses["401"] = &openAPIResponse{Description: "Unauthorized"}
+			op.Responses["403"] = &openAPIResponse{Description: "Forbidden"}
+		}
+
+		var form *openAPISchema
+		multipart := false
+		lParamNames := strings.Split(paramNames, ",")
+		lParamTypes := strings.Split(paramTypes, ",")
+		for i, p := range lParamNames {
+			p = strings.TrimSpace(p)
+			paramType := strings.TrimSpace(lParamTypes[i])
+
+			if p == "" {
+				// the method has no parameters.
+				continue
+			}
+			if p == reqBodyVarName {
+				schema := doc.schemaOf(paramTypesInfo[i], true)
+				op.RequestBody = &openAPIRequestBody{
+					Required: true,
+					Content: map[string]*openAPIMediaType{
+						"application/json": {Schema: schema},
+					},
+				}
+				if isStructType(paramTypesInfo[i]) {
+					// structs are also decoded from forms.
+					op.RequestBody.Content["application/x-www-form-urlencoded"] = &openAPIMediaType{Schema: schema}
+					op.RequestBody.Content["multipart/form-data"] = &openAPIMediaType{Schema: schema}
+				}
+				continue
+			}
+			if isPrincipalParam(p, paramType, paramTypesInfo[i]) {
+				op.Responses["401"] = &openAPIResponse{Description: "Unauthorized"}
+				op.Responses["403"] = &openAPIResponse{Description: "Forbidden"}
+				continue
+			}
+			if paramType == "httper.Cookier" || paramType == "httper.Sessionner" ||
+				paramType == "http.ResponseWriter" || paramType == "*http.Request" {
+				continue
+			}
+			fields := getBindFields(paramTypesInfo[i])
+			if len(fields) == 0 {
+				if !isConvetionnedParam(mode, p) {
+					continue
+				}
+				prefix := getParamConvention(mode, p)
+				fields = append(fields, bindField{Prefix: prefix, Name: getParamName(prefix, p), Type: paramTypesInfo[i]})
+			}
+			for _, f := range fields {
+				name := f.Name
+				if f.Prefix == "file" {
+					// files are binary parts of a multipart form,
+					// their types have no schema.
+					if form == nil {
+						form = &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{}}
+					}
+					schema := &openAPISchema{Type: "string", Format: "binary"}
+					if sliceElem(f.Type) != nil {
+						schema = &openAPISchema{Type: "array", Items: schema}
+					}
+					form.Properties[name] = schema
+					multipart = true
+					continue
+				}
+				schema := doc.schemaOf(f.Type, false)
+
+				param := &openAPIParameter{Name: name, Schema: schema}
+				switch f.Prefix {
+				case "get", "req":
+					param.In = "query"
+				case "url", "route":
+					if strings.Contains(route.Path, "{"+name) {
+						param.In = "path"
+						param.Required = true
+					} else if f.Prefix == "url" {
+						// url data are also read from the query.
+						param.In = "query"
+					} else {
+						continue
+					}
+				case "cookie":
+					param.In = "cookie"
+				case "header":
+					param.In = "header"
+				case "post":
+					if form == nil {
+						form = &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{}}
+					}
+					form.Properties[name] = schema
+					continue
+				default:
+					continue
+				}
+				if schema.Type == "array" {
+					explode := true
+					param.Explode = &explode
+				}
+				op.Parameters = append(op.Parameters, param)
+			}
+		}
+		if form != nil && op.RequestBody == nil {
+			contentType := "application/x-www-form-urlencoded"
+			if multipart {
+				contentType = "multipart/form-data"
+			}
+			op.RequestBody = &openAPIRequestBody{
+				Content: map[string]*openAPIMediaType{
+					contentType: {Schema: form},
+				},
+			}
+		}
+
+		methods := getRouteMethods(route, op.RequestBody != nil)
+		path := openAPIPath(route.Path)
+		if _, ok := doc.Paths[path]; !ok {
+			doc.Paths[path] = map[string]*openAPIOperation{}
+		}
+		for _, method := range methods {
+			methodOp := *op
+			if len(methods) > 1 {
+				// operation ids must be unique.
+				methodOp.OperationID += "." + strings.ToLower(method)
+			}
+			doc.Paths[path][strings.ToLower(method)] = &methodOp
+		}
+	}
+
+	return nil
+}
+
+// openAPIPath removes the patterns of the variables of a route path,
+// /tomates/{id:[0-9]+} becomes /tomates/{id}.
+func openAPIPath(path string) string {
+	ret := ""
+	for {
+		start := strings.Index(path, "{")
+		end := strings.Index(path, "}")
+		if start < 0 || end < start {
+			break
+		}
+		ret += path[:start] + "{" + strings.SplitN(path[start+1:end], ":", 2)[0] + "}"
+		path = path[end+1:]
+	}
+	return ret + path
+}
+
+// schemaOf returns the schema of t, when body is true
+// the schema describes a json payload.
+func (d *openAPIDoc) schemaOf(t types.Type, body bool) *openAPISchema {
+	if t == nil {
+		return &openAPISchema{}
+	}
+	if named, ok := t.(*types.Named); ok && named.Obj().Pkg() != nil {
+		if named.Obj().Pkg().Path() == "time" && named.Obj().Name() == "Time" {
+			return &openAPISchema{Type: "string", Format: "date-time"}
+		}
+	}
+	if !body && (isTextUnmarshaler(t) || isDurationType(t)) {
+		return &openAPISchema{Type: "string"}
+	}
+	switch x := t.(type) {
+	case *types.Pointer:
+		return d.schemaOf(x.Elem(), body)
+	case *types.Named:
+		if _, ok := x.Underlying().(*types.Struct); ok {
+			name := x.Obj().Name()
+			if _, ok := d.Components.Schemas[name]; !ok {
+				// register before recursing to support self referencing types.
+				d.Components.Schemas[name] = &openAPISchema{}
+				*d.Components.Schemas[name] = *d.schemaOf(x.Underlying(), body)
+			}
+			return &openAPISchema{Ref: "#/components/schemas/" + name}
+		}
+		return d.schemaOf(x.Underlying(), body)
+	case *types.Basic:
+		return basicSchema(x)
+	case *types.Slice:
+		if b, ok := x.Elem().(*types.Basic); ok && b.Kind() == types.Byte {
+			return &openAPISchema{Type: "string", Format: "byte"}
+		}
+		return &openAPISchema{Type: "array", Items: d.schemaOf(x.Elem(), body)}
+	case *types.Array:
+		return &openAPISchema{Type: "array", Items: d.schemaOf(x.Elem(), body)}
+	case *types.Map:
+		return &openAPISchema{Type: "object", AdditionalProperties: d.schemaOf(x.Elem(), body)}
+	case *types.Struct:
+		ret := &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{}}
+		d.structProperties(x, ret.Properties)
+		return ret
+	}
+	return &openAPISchema{}
+}
+
+// structProperties adds the json properties of s to props.
+func (d *openAPIDoc) structProperties(s *types.Struct, props map[string]*openAPISchema) {
+	for i := 0; i < s.NumFields(); i++ {
+		field := s.Field(i)
+		tag := reflect.StructTag(s.Tag(i)).Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous() && name == "" {
+			ft := field.Type()
+			if ptr, ok := ft.(*types.Pointer); ok {
+				ft = ptr.Elem()
+			}
+			if embed, ok := ft.Underlying().(*types.Struct); ok {
+				d.structProperties(embed, props)
+				continue
+			}
+		}
+		if !field.Exported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name()
+		}
+		props[name] = d.schemaOf(field.Type(), true)
+	}
+}
+
+func basicSchema(b *types.Basic) *openAPISchema {
+	switch b.Kind() {
+	case types.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case types.Int8, types.Int16, types.Int32, types.Uint8, types.Uint16:
+		return &openAPISchema{Type: "integer", Format: "int32"}
+	case types.Int, types.Int64, types.Uint, types.Uint32, types.Uint64:
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	case types.Float32:
+		return &openAPISchema{Type: "number", Format: "float"}
+	case types.Float64:
+		return &openAPISchema{Type: "number", Format: "double"}
+	case types.String:
+		return &openAPISchema{Type: "string"}
+	}
+	return &openAPISchema{}
+}
@@ -16,6 +16,9 @@ httper - demo/*Controller:ControllerJSON | grep -F "embed *Controller" || exit 1
 httper - demo/Controller:*ControllerJSON | grep "embed Controller" || exit 1;
 httper - demo/*Controller:*ControllerJSON | grep -F "embed *Controller" || exit 1;
 
+httper -interface - demo/*Controller:ControllerJSON | grep "embed ControllerJSONEmbed" || exit 1;
+httper -interface - demo/*Controller:ControllerJSON | grep "type ControllerJSONEmbedMock struct" || exit 1;
+
 rm -fr gen_test
 httper demo/Controller:gen_test/ControllerJSON || exit 1;
 ls -al gen_test | grep "controllerjson.go" || exit 1;
@@ -23,6 +26,13 @@ cat gen_test/controllerjson.go | grep -F "GetByID(w http.ResponseWriter" || exit
 cat gen_test/controllerjson.go | grep "package gen_test" || exit 1;
 rm -fr gen_test
 
+httper -tests -interface demo/Controller:gen_test/ControllerJSON || exit 1;
+ls -al gen_test | grep "controllerjson_test.go" || exit 1;
+ls -al gen_test | grep "controllerjson_mock_test.go" || exit 1;
+cat gen_test/controllerjson.go | grep "type ControllerJSONEmbedMock struct" && exit 1;
+cat gen_test/controllerjson_test.go | grep -F "func TestControllerJSONGetByID(t *testing.T)" || exit 1;
+rm -fr gen_test
+
 rm -fr demo/*gen.go
 go generate demo/main.go
 ls -al demo | grep "controllerjsongen.go" || exit 1;
@@ -36,6 +46,8 @@ ls -al demo | grep "controllerjsongen.go" || exit 1;
 cat demo/controllerjsongen.go | grep "package main" || exit 1;
 cat demo/controllerjsongen.go | grep "NewControllerJSONGen(" || exit 1;
 go run demo/*.go | grep "Red" || exit 1;
+ls -al demo | grep "controllerhttpgen_test.go" || exit 1;
+go test github.com/mh-cbon/httper/demo || exit 1;
 # rm -fr demo/gen # keep it for demo
 
 # go test
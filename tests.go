@@ -0,0 +1,772 @@
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mh-cbon/astutil"
+	httper "github.com/mh-cbon/httper/lib"
+	"github.com/mh-cbon/httper/utils"
+)
+
+// testParam is a request data bound to a parameter of a handler.
+type testParam struct {
+	Label     string
+	Prefix    string
+	Name      string
+	Valid     string
+	Malformed string
+	// Arg is the expression of the bound value in the arguments of a call,
+	// Want is the expression of the value bound from Valid.
+	Arg  string
+	Want string
+}
+
+// processTests generates the httptest tests of the httper of todo,
+// each handler is invoked on the mock of its embedded value with missing,
+// malformed and valid data to check the binding of its parameters.
+func processTests(mode string, todo utils.TransformArg, fileOut *utils.FileOut) error {
+
+	dest := &fileOut.Body
+	srcName := todo.FromTypeName
+	destName := todo.ToTypeName
+
+	prog := astutil.GetProgramFast(todo.FromPkgPath)
+	pkg := prog.Package(todo.FromPkgPath)
+	foundMethods := astutil.FindMethods(pkg)
+	findDurationTypes(prog)
+	qualifier := importQualifier(pkg, fileOut)
+
+	srcConcrete := astutil.GetUnpointedType(srcName)
+	destConcrete := astutil.GetUnpointedType(destName)
+
+	fileOut.AddImport("bytes", "")
+	fileOut.AddImport("encoding/json", "")
+	fileOut.AddImport("io", "")
+	fileOut.AddImport("io/ioutil", "")
+	fileOut.AddImport("mime/multipart", "")
+	fileOut.AddImport("net/http", "")
+	fileOut.AddImport("net/http/httptest", "")
+	fileOut.AddImport("net/url", "")
+	fileOut.AddImport("reflect", "")
+	fileOut.AddImport("strings", "")
+	fileOut.AddImport("testing", "")
+	fileOut.AddImport("time", "")
+	fileOut.AddImport("github.com/mh-cbon/httper/lib", "httper")
+
+	setVars := ""
+	if mode == gorillaMode {
+		fileOut.AddImport("github.com/gorilla/mux", "")
+		setVars = `if len(tc.vars) > 0 {
+		req = mux.SetURLVars(req, tc.vars)
+	}`
+	}
+
+	// the constructor requires the named middlewares of every method.
+	middlewares := []string{}
+	registered := map[string]bool{}
+	for _, m := range wrappedMethods(pkg, srcName) {
+		for _, name := range getAnnotationList(getAnnotations(astutil.GetComment(prog, m.Pos())), "middleware") {
+			if !registered[name] {
+				registered[name] = true
+				middlewares = append(middlewares,
+					fmt.Sprintf("httper.WithNamedMiddleware(%q, func(h http.Handler) http.Handler { return h }),\n", name))
+			}
+		}
+	}
+
+	fmt.Fprintf(dest, `
+// testCase%v is a request of a handler of %v.
+type testCase%v struct {
+	name        string
+	query       url.Values
+	form        url.Values
+	header      http.Header
+	cookies     map[string]string
+	vars        map[string]string
+	// files are the contents of the multipart files, by name.
+	files       map[string]string
+	body        string
+	contentType string
+	// anonymous requests are not authenticated.
+	anonymous bool
+	// wantCode is the code of the expected binding error,
+	// the binding must succeed when it is empty.
+	wantCode string
+	// wantStatus is the status of the response, when it is not 0.
+	wantStatus int
+	// wantArgs checks the arguments of the single call of the method.
+	wantArgs func(t *testing.T, args []interface{})
+}
+
+// runTest%v sends the request of tc to h.
+func runTest%v(t *testing.T, h http.HandlerFunc, method string, tc testCase%v) *httptest.ResponseRecorder {
+	t.Helper()
+	var body io.Reader = strings.NewReader(tc.body)
+	contentType := tc.contentType
+	if len(tc.form) > 0 {
+		body = strings.NewReader(tc.form.Encode())
+		contentType = "application/x-www-form-urlencoded"
+	}
+	if len(tc.files) > 0 {
+		buf := &bytes.Buffer{}
+		mw := multipart.NewWriter(buf)
+		for k, v := range tc.form {
+			for _, s := range v {
+				mw.WriteField(k, s)
+			}
+		}
+		for k, v := range tc.files {
+			f, err := mw.CreateFormFile(k, k+".txt")
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.Write([]byte(v))
+		}
+		mw.Close()
+		body = buf
+		contentType = mw.FormDataContentType()
+	}
+	req := httptest.NewRequest(method, "/?"+tc.query.Encode(), body)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for k, v := range tc.header {
+		req.Header[k] = v
+	}
+	for k, v := range tc.cookies {
+		req.AddCookie(&http.Cookie{Name: k, Value: v})
+	}
+	%v
+	w := httptest.NewRecorder()
+	h(w, req)
+	problem := &httper.Problem{}
+	if strings.HasPrefix(w.Header().Get("Content-Type"), "application/problem+json") {
+		json.Unmarshal(w.Body.Bytes(), problem)
+	}
+	if tc.wantCode != "" {
+		if w.Code != http.StatusBadRequest || problem.Code != tc.wantCode {
+			t.Fatalf("want a %%v error, got %%v %%v", tc.wantCode, w.Code, w.Body.String())
+		}
+	} else if problem.Code == "invalid_parameter" || problem.Code == "invalid_body" {
+		t.Fatalf("unexpected binding error %%v %%v", w.Code, w.Body.String())
+	}
+	if tc.wantStatus != 0 && w.Code != tc.wantStatus {
+		t.Fatalf("want the status %%v, got %%v %%v", tc.wantStatus, w.Code, w.Body.String())
+	}
+	return w
+}
+
+// checkCalls%v checks the calls of the method recorded for tc,
+// it must not be invoked on an error, it must be invoked once otherwise.
+func checkCalls%v(t *testing.T, calls []%v, w *httptest.ResponseRecorder, tc testCase%v) {
+	t.Helper()
+	if (tc.wantCode != "" || tc.wantStatus >= 400) && len(calls) > 0 {
+		t.Fatalf("the method must not be invoked on an error")
+	}
+	if (tc.wantArgs != nil || w.Code < 400) && len(calls) != 1 {
+		t.Fatalf("the method must be invoked once, got %%v calls, %%v %%v", len(calls), w.Code, w.Body.String())
+	}
+	if tc.wantArgs != nil {
+		tc.wantArgs(t, calls[0].Args)
+	}
+}
+
+// checkArg%v fails when the argument name of a call is not want.
+func checkArg%v(t *testing.T, name string, got, want interface{}) {
+	t.Helper()
+	if x, ok := got.(time.Time); ok {
+		if y, ok := want.(time.Time); ok && x.Equal(y) {
+			return
+		}
+	} else if reflect.DeepEqual(got, want) {
+		return
+	}
+	t.Fatalf("want the argument %%v %%#v, got %%#v", name, want, got)
+}
+
+// readArg%v returns the content of an argument of a call,
+// a file, the first of a list of files, or a reader.
+func readArg%v(t *testing.T, arg interface{}) string {
+	t.Helper()
+	if files, ok := arg.([]*multipart.FileHeader); ok && len(files) > 0 {
+		arg = files[0]
+	}
+	if fh, ok := arg.(*multipart.FileHeader); ok && fh != nil {
+		f, err := fh.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		arg = f
+	}
+	r, ok := arg.(io.Reader)
+	if !ok {
+		return ""
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+// testOptions%v returns the options of the handlers,
+// they register the named middlewares.
+func testOptions%v() []httper.Option {
+	return []httper.Option{
+		%v}
+}
+
+// testAuth%v returns the options authenticating the requests as principal,
+// and authorizing them.
+func testAuth%v(principal interface{}) []httper.Option {
+	return []httper.Option{
+		httper.WithAuthenticator(httper.AuthenticatorFunc(func(r *http.Request) (interface{}, error) {
+			return principal, nil
+		})),
+		httper.WithAuthorizer(httper.AuthorizerFunc(func(r *http.Request, principal interface{}, roles, permissions []string) error {
+			return nil
+		})),
+	}
+}
+`, destConcrete, destConcrete, destConcrete,
+		destConcrete, destConcrete, destConcrete, setVars,
+		destConcrete, destConcrete, embedCallName(destName), destConcrete,
+		destConcrete, destConcrete,
+		destConcrete, destConcrete,
+		destConcrete, destConcrete, strings.Join(middlewares, ""),
+		destConcrete, destConcrete)
+
+	for _, m := range foundMethods[srcConcrete] {
+		methodName := astutil.MethodName(m)
+		paramNames := astutil.MethodParamNames(m)
+		paramTypes := astutil.MethodParamTypes(m)
+		paramTypesInfo := methodParamTypesInfo(pkg, m)
+
+		// ensure it is desired to facade this method.
+		if astutil.IsExported(methodName) == false {
+			continue
+		}
+		if methodName == "UnmarshalJSON" || methodName == "MarshalJSON" {
+			continue
+		}
+
+		comment := astutil.GetComment(prog, m.Pos())
+		annotations := getAnnotations(comment)
+		route, err := getRoute(mode, methodName, paramNames, paramTypesInfo, annotations)
+		if err != nil {
+			return fmt.Errorf("%v.%v: %v", srcName, methodName, err)
+		}
+
+		// the rules of the @validate annotations, by parameter.
+		rules := map[string][]string{}
+		for _, v := range getAnnotationValues(comment, "validate") {
+			if k := strings.SplitN(v, " ", 2); len(k) == 2 {
+				p := strings.TrimSpace(k[0])
+				rules[p] = append(rules[p], strings.TrimSpace(k[1]))
+			}
+		}
+
+		needsPrincipal := len(getAnnotationList(annotations, "roles")) > 0 ||
+			len(getAnnotationList(annotations, "permission")) > 0
+		principal := ""
+		hasBody := false
+		decodesBody := false
+		readsBody := false
+		params := []testParam{}
+		checks := []string{}
+		// the values required by the validation rules.
+		required := []string{}
+
+		lParamNames := strings.Split(paramNames, ",")
+		lParamTypes := strings.Split(paramTypes, ",")
+		for i, p := range lParamNames {
+			p = strings.TrimSpace(p)
+			paramType := strings.TrimSpace(lParamTypes[i])
+			if p == "" {
+				continue
+			}
+			arg := fmt.Sprintf("args[%v]", i)
+			if isPrincipalParam(p, paramType, paramTypesInfo[i]) {
+				needsPrincipal = true
+				if principal == "" {
+					principal = principalLiteral(paramTypesInfo[i], qualifier)
+				}
+				checks = append(checks, fmt.Sprintf("checkArg%v(t, %q, %v, principal)", destConcrete, p, arg))
+				continue
+			}
+			// the rules apply to the zero values, the nil pointers are absent.
+			if _, isPtr := paramTypesInfo[i].(*types.Pointer); !isPtr && hasRules(strings.Join(rules[p], ",")) {
+				required = append(required, p)
+			}
+			required = append(required, requiredPaths(paramTypesInfo[i], p, map[types.Type]bool{})...)
+			if p == reqBodyVarName {
+				hasBody = true
+				decodesBody = !isBodyReaderType(paramTypesInfo[i])
+				if !decodesBody {
+					// the reader is the body of the valid case.
+					readsBody = true
+					checks = append(checks, fmt.Sprintf("checkArg%v(t, %q, readArg%v(t, %v), %q)", destConcrete, p, destConcrete, arg, "x"))
+				}
+				continue
+			}
+			if fields := getBindFields(paramTypesInfo[i]); len(fields) > 0 {
+				typeName := types.TypeString(paramTypesInfo[i], qualifier)
+				for _, f := range fields {
+					fieldType := f.Type
+					fieldArg := fmt.Sprintf("%v.(%v).%v", arg, typeName, f.Path)
+					if f.Prefix == "file" && !isFileHeaderType(fieldType) && !isFileHeaderType(sliceElem(fieldType)) {
+						// the bound files are file headers.
+						continue
+					}
+					if ptr, isPtr := fieldType.(*types.Pointer); isPtr && f.Prefix != "file" {
+						fieldType = ptr.Elem()
+						fieldArg = "*" + fieldArg
+					}
+					fieldRules := []string{}
+					if f.Rules != "" {
+						fieldRules = append(fieldRules, f.Rules)
+					}
+					if param, ok := newTestParam(mode, p+"."+f.Path, f.Prefix, f.Name, fieldType, fieldRules, qualifier); ok {
+						param.Arg = fieldArg
+						params = append(params, param)
+					}
+				}
+				continue
+			}
+			if !isConvetionnedParam(mode, p) {
+				continue
+			}
+			prefix := getParamConvention(mode, p)
+			if param, ok := newTestParam(mode, p, prefix, getParamName(prefix, p), paramTypesInfo[i], rules[p], qualifier); ok {
+				param.Arg = arg
+				params = append(params, param)
+			}
+		}
+		if decodesBody || readsBody {
+			// the post data and the files can not be sent along a body.
+			sent := []testParam{}
+			for _, p := range params {
+				if p.Prefix != "post" && p.Prefix != "file" {
+					sent = append(sent, p)
+				}
+			}
+			params = sent
+		}
+		sent := map[string]bool{}
+		for i, p := range params {
+			if p.Prefix == "url" && !strings.Contains(route.Path, "{"+p.Name) {
+				// url data are also read from the query.
+				params[i].Prefix = "get"
+			}
+			if p.Prefix == "file" {
+				p.Arg = fmt.Sprintf("readArg%v(t, %v)", destConcrete, p.Arg)
+			}
+			hasBody = hasBody || p.Prefix == "post" || p.Prefix == "file"
+			sent[p.Label] = true
+			checks = append(checks, fmt.Sprintf("checkArg%v(t, %q, %v, %v)", destConcrete, p.Label, p.Arg, p.Want))
+		}
+		// the valid case can not be generated when a required value is not sent.
+		unsatisfied := []string{}
+		for _, r := range required {
+			if !sent[r] {
+				unsatisfied = append(unsatisfied, r)
+			}
+		}
+
+		wantArgs := fmt.Sprintf("wantArgs: func(t *testing.T, args []interface{}) {\n%v}", strings.Join(append(checks, ""), "\n"))
+		valid := ""
+		if len(unsatisfied) > 0 {
+			valid = fmt.Sprintf("// the valid case is not generated, %v can not be sent.\n", strings.Join(unsatisfied, ", "))
+		}
+
+		cases := ""
+		if decodesBody {
+			cases += testCaseLiteral("missing", params, "", false, `body: "", wantCode: "invalid_body"`)
+			if valid == "" {
+				cases += testCaseLiteral("valid", params, "", true, `body: "null", `+wantArgs)
+			}
+		} else if readsBody {
+			cases += testCaseLiteral("missing", params, "", false, "")
+			if valid == "" {
+				cases += testCaseLiteral("valid", params, "", true, `body: "x", `+wantArgs)
+			}
+		} else {
+			cases += testCaseLiteral("missing", params, "", false, "")
+			if valid == "" {
+				cases += testCaseLiteral("valid", params, "", true, wantArgs)
+			}
+		}
+		cases += valid
+		for _, p := range params {
+			if p.Malformed == "" {
+				continue
+			}
+			extra := `wantCode: "invalid_parameter"`
+			if decodesBody {
+				extra = `body: "null", ` + extra
+			}
+			cases += testCaseLiteral("malformed "+p.Label, params, p.Label, true, extra)
+		}
+		if decodesBody {
+			cases += testCaseLiteral("malformed "+reqBodyVarName, params, "", true,
+				`body: "{", contentType: "application/json", wantCode: "invalid_body"`)
+		}
+
+		declarePrincipal := ""
+		authenticate := ""
+		if needsPrincipal {
+			if principal == "" {
+				principal = "struct{}{}"
+			}
+			cases += testCaseLiteral("unauthenticated", nil, "", false, "anonymous: true, wantStatus: http.StatusUnauthorized")
+			declarePrincipal = fmt.Sprintf("principal := %v\n", principal)
+			authenticate = fmt.Sprintf("if !tc.anonymous {\nopts = append(opts, testAuth%v(principal)...)\n}\n", destConcrete)
+		}
+
+		fmt.Fprintf(dest, "\n// Test%v%v checks the binding of the parameters of %v.%v.\n", destConcrete, methodName, srcName, methodName)
+		fmt.Fprintf(dest, `func Test%v%v(t *testing.T) {
+%vtests := []testCase%v{
+%v}
+for _, tc := range tests {
+	tc := tc
+	t.Run(tc.name, func(t *testing.T) {
+		mock := &%v{}
+		opts := testOptions%v()
+		%vh := New%vWithOptions(mock, opts...)
+		w := runTest%v(t, h.%v, %q, tc)
+		checkCalls%v(t, mock.Calls(), w, tc)
+	})
+}
+}
+`, destConcrete, methodName, declarePrincipal, destConcrete, cases,
+			embedMockName(destName), destConcrete, authenticate, destConcrete,
+			destConcrete, methodName, getRouteMethods(route, hasBody)[0],
+			destConcrete)
+	}
+
+	return nil
+}
+
+// newTestParam returns the testParam of the data name of prefix to bind to the type t,
+// its valid value satisfies the rules. It is false when the data is not provided in mode,
+// or when its values can not be sent or guessed.
+func newTestParam(mode, label, prefix, name string, t types.Type, rules []string, qualifier types.Qualifier) (testParam, bool) {
+	switch prefix {
+	case "get", "req", "url", "post", "cookie", "header", "route", "file":
+	default:
+		return testParam{}, false
+	}
+	if !hasDataProvider(mode, prefix) {
+		return testParam{}, false
+	}
+	if prefix == "file" {
+		// the file content is the data name, Arg reads it.
+		if !isFileHeaderType(t) && !isFileHeaderType(sliceElem(t)) && !isIOReaderType(t) {
+			return testParam{}, false
+		}
+		return testParam{Label: label, Prefix: prefix, Name: name, Valid: name, Want: strconv.Quote(name)}, true
+	}
+	valid, malformed, ok := sampleValues(t, rules)
+	if !ok {
+		return testParam{}, false
+	}
+	return testParam{Label: label, Prefix: prefix, Name: name, Valid: valid, Malformed: malformed,
+		Want: sampleLiteral(t, valid, qualifier)}, true
+}
+
+// sampleValues returns a valid string of t satisfying the rules, and a malformed one,
+// malformed is empty when any string is valid.
+func sampleValues(t types.Type, rules []string) (valid, malformed string, ok bool) {
+	valid, malformed, ok = baseSampleValues(t)
+	if !ok || len(rules) == 0 {
+		return valid, malformed, ok
+	}
+	for _, candidate := range append([]string{valid}, ruleCandidates(t, rules)...) {
+		v, isValue := sampleValue(t, candidate)
+		if !isValue {
+			continue
+		}
+		satisfied := true
+		for _, r := range rules {
+			satisfied = satisfied && len(httper.Violations{}.Check("", v, r)) == 0
+		}
+		if satisfied {
+			return candidate, malformed, true
+		}
+	}
+	return "", "", false
+}
+
+// baseSampleValues returns a valid and a malformed string of t,
+// malformed is empty when any string is valid.
+func baseSampleValues(t types.Type) (valid, malformed string, ok bool) {
+	if isTimeType(t, "Time") {
+		return "2017-01-02T15:04:05Z", "x", true
+	}
+	if isTextUnmarshaler(t) {
+		if _, isPtr := t.(*types.Pointer); isPtr {
+			return "", "", false
+		}
+		// the value is unmarshaled by the test.
+		return "x", "", true
+	}
+	if isDurationType(t) {
+		return "1s", "x", true
+	}
+	if isBytesType(t) {
+		return "x", "", true
+	}
+	switch x := t.Underlying().(type) {
+	case *types.Slice:
+		return baseSampleValues(x.Elem())
+	case *types.Basic:
+		info := x.Info()
+		switch {
+		case info&types.IsString != 0:
+			return "x", "", true
+		case info&types.IsBoolean != 0:
+			return "true", "x", true
+		case info&types.IsInteger != 0:
+			return "1", "x", true
+		case info&types.IsFloat != 0:
+			return "1.5", "x", true
+		}
+	}
+	return "", "", false
+}
+
+// ruleCandidates returns the strings of t which may satisfy the rules,
+// the options of oneof, the limits of min and max, or strings of their length.
+func ruleCandidates(t types.Type, rules []string) []string {
+	isString := false
+	if b, isBasic := t.Underlying().(*types.Basic); isBasic && b.Info()&types.IsString != 0 {
+		isString = true
+	}
+	ret := []string{}
+	for _, r := range rules {
+		for _, rule := range strings.Split(r, ",") {
+			kv := strings.SplitN(strings.TrimSpace(rule), "=", 2)
+			if len(kv) < 2 {
+				continue
+			}
+			switch kv[0] {
+			case "oneof":
+				ret = append(ret, strings.Fields(kv[1])...)
+			case "min", "max":
+				limit, err := strconv.ParseFloat(kv[1], 64)
+				if err != nil {
+					continue
+				}
+				ret = append(ret, kv[1],
+					strconv.FormatFloat(math.Ceil(limit), 'f', -1, 64),
+					strconv.FormatFloat(math.Floor(limit), 'f', -1, 64))
+				if isString && limit >= 1 && limit < 1024 {
+					ret = append(ret, strings.Repeat("x", int(limit)))
+				}
+			}
+		}
+	}
+	return ret
+}
+
+// sampleValue returns the value of t bound from s, to check it against the validation rules.
+func sampleValue(t types.Type, s string) (interface{}, bool) {
+	if isTimeType(t, "Time") {
+		v, err := time.Parse(time.RFC3339, s)
+		return v, err == nil
+	}
+	if isTextUnmarshaler(t) {
+		return nil, false
+	}
+	if isDurationType(t) {
+		d, err := time.ParseDuration(s)
+		return d, err == nil
+	}
+	if isBytesType(t) {
+		return []byte(s), true
+	}
+	switch x := t.Underlying().(type) {
+	case *types.Slice:
+		v, ok := sampleValue(x.Elem(), s)
+		return []interface{}{v}, ok
+	case *types.Basic:
+		info := x.Info()
+		switch {
+		case info&types.IsString != 0:
+			return s, true
+		case info&types.IsBoolean != 0:
+			v, err := strconv.ParseBool(s)
+			return v, err == nil
+		case info&types.IsUnsigned != 0:
+			v, err := strconv.ParseUint(s, 10, 64)
+			return v, err == nil
+		case info&types.IsInteger != 0:
+			v, err := strconv.ParseInt(s, 10, 64)
+			return v, err == nil
+		case info&types.IsFloat != 0:
+			v, err := strconv.ParseFloat(s, 64)
+			return v, err == nil
+		}
+	}
+	return nil, false
+}
+
+// sampleLiteral returns the expression of the value of t bound from s.
+func sampleLiteral(t types.Type, s string, qualifier types.Qualifier) string {
+	typeName := types.TypeString(t, qualifier)
+	if isTimeType(t, "Time") {
+		v, _ := time.Parse(time.RFC3339, s)
+		return fmt.Sprintf("time.Date(%d, %d, %d, %d, %d, %d, 0, time.UTC)",
+			v.Year(), v.Month(), v.Day(), v.Hour(), v.Minute(), v.Second())
+	}
+	if isTextUnmarshaler(t) {
+		return fmt.Sprintf("func() %v {\nvar v %v\nv.UnmarshalText([]byte(%q))\nreturn v\n}()", typeName, typeName, s)
+	}
+	if isDurationType(t) {
+		d, _ := time.ParseDuration(s)
+		return fmt.Sprintf("%v(%d)", typeName, int64(d))
+	}
+	if isBytesType(t) {
+		return fmt.Sprintf("%v(%q)", typeName, s)
+	}
+	switch x := t.Underlying().(type) {
+	case *types.Slice:
+		return fmt.Sprintf("%v{%v}", typeName, sampleLiteral(x.Elem(), s, qualifier))
+	case *types.Basic:
+		literal := s
+		if x.Info()&types.IsString != 0 {
+			literal = strconv.Quote(s)
+		}
+		switch typeName {
+		case "string", "bool", "int", "float64":
+			return literal
+		}
+		return fmt.Sprintf("%v(%v)", typeName, literal)
+	}
+	return s
+}
+
+// principalLiteral returns the expression of a principal of type t.
+func principalLiteral(t types.Type, qualifier types.Qualifier) string {
+	typeName := types.TypeString(t, qualifier)
+	if ptr, ok := t.(*types.Pointer); ok {
+		if _, isStruct := ptr.Elem().Underlying().(*types.Struct); isStruct {
+			return "&" + types.TypeString(ptr.Elem(), qualifier) + "{}"
+		}
+		return fmt.Sprintf("new(%v)", types.TypeString(ptr.Elem(), qualifier))
+	}
+	switch t.Underlying().(type) {
+	case *types.Interface:
+		// a value implementing the interface.
+		return fmt.Sprintf("struct{ %v }{}", typeName)
+	case *types.Basic:
+		if valid, _, ok := baseSampleValues(t); ok {
+			return sampleLiteral(t, valid, qualifier)
+		}
+	}
+	return typeName + "{}"
+}
+
+// hasRules returns true when the comma separated rules are not blank.
+func hasRules(rules string) bool {
+	return strings.Trim(rules, " ,") != ""
+}
+
+// requiredPaths returns the paths of the fields of the struct t having rules
+// in their httper.ValidateTag, except the pointers, the fields of the nested structs are included.
+func requiredPaths(t types.Type, path string, seen map[types.Type]bool) []string {
+	if t == nil || seen[t] {
+		return nil
+	}
+	seen[t] = true
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	s, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+	ret := []string{}
+	for i := 0; i < s.NumFields(); i++ {
+		field := s.Field(i)
+		if !field.Exported() {
+			continue
+		}
+		fieldPath := path + "." + field.Name()
+		_, isPtr := field.Type().(*types.Pointer)
+		if tag := reflect.StructTag(s.Tag(i)).Get(httper.ValidateTag); !isPtr && tag != "-" && hasRules(tag) {
+			ret = append(ret, fieldPath)
+		}
+		// the nil pointers are not validated,
+		// the embedded ones are allocated by the binding.
+		if !isPtr || field.Anonymous() {
+			ret = append(ret, requiredPaths(field.Type(), fieldPath, seen)...)
+		}
+	}
+	return ret
+}
+
+// isTimeType returns true when t is the type name of the time package.
+func isTimeType(t types.Type, name string) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "time" && named.Obj().Name() == name
+}
+
+// testCaseLiteral returns a test case literal of params and the extra fields,
+// their values are valid when withValues is true, except the malformed label.
+func testCaseLiteral(name string, params []testParam, malformed string, withValues bool, extra string) string {
+	fields := map[string][]string{}
+	order := []string{}
+	add := func(field, entry string) {
+		if _, ok := fields[field]; !ok {
+			order = append(order, field)
+		}
+		fields[field] = append(fields[field], entry)
+	}
+	if withValues {
+		for _, p := range params {
+			value := p.Valid
+			if p.Label == malformed {
+				value = p.Malformed
+			}
+			entry := fmt.Sprintf("%q: {%q}", p.Name, value)
+			switch p.Prefix {
+			case "get", "req":
+				add("query", entry)
+			case "post":
+				add("form", entry)
+			case "header":
+				add("header", entry)
+			case "cookie":
+				add("cookies", fmt.Sprintf("%q: %q", p.Name, value))
+			case "url", "route":
+				add("vars", fmt.Sprintf("%q: %q", p.Name, value))
+			case "file":
+				add("files", fmt.Sprintf("%q: %q", p.Name, value))
+			}
+		}
+	}
+	fieldTypes := map[string]string{
+		"query":   "url.Values",
+		"form":    "url.Values",
+		"header":  "http.Header",
+		"cookies": "map[string]string",
+		"vars":    "map[string]string",
+		"files":   "map[string]string",
+	}
+	ret := fmt.Sprintf("{name: %q", name)
+	for _, field := range order {
+		ret += fmt.Sprintf(", %v: %v{%v}", field, fieldTypes[field], strings.Join(fields[field], ", "))
+	}
+	if extra != "" {
+		ret += ", " + extra
+	}
+	return ret + "},\n"
+}